@@ -0,0 +1,138 @@
+// Command odatagen generates Go models and clients for an OData v4 service from its $metadata document.
+//
+// It can be used from go:generate, where the package name defaults to the package of the file:
+//
+//	//go:generate go run github.com/Uffe-Code/go-odata/cmd/odatagen -url https://services.odata.org/TripPinRESTierService -header "Authorization: Bearer $TOKEN"
+//	//go:generate go run github.com/Uffe-Code/go-odata/cmd/odatagen -file metadata.xml -include People,Airlines
+//	//go:generate go run github.com/Uffe-Code/go-odata/cmd/odatagen -file metadata.xml -config odatagen.yaml
+//
+// Use -save-metadata to store the fetched $metadata next to the generated code, so it can be regenerated offline with -file.
+//
+// With -check, no code is written, and the command exits with status 1 when the generated code is out of date,
+// or with status 2 when it could not be checked, e.g. because $metadata could not be fetched.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/Uffe-Code/go-odata/modelGenerator"
+	"io"
+	"os"
+	"strings"
+)
+
+// listFlag is a flag which can be repeated, and takes comma separated values
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// headerFlag is a repeatable flag for HTTP headers in the form "Name: Value"
+type headerFlag map[string]string
+
+func (h headerFlag) String() string {
+	headers := make([]string, 0, len(h))
+	for name, value := range h {
+		headers = append(headers, name+": "+value)
+	}
+	return strings.Join(headers, ", ")
+}
+
+func (h headerFlag) Set(value string) error {
+	name, headerValue, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header %q must have the form \"Name: Value\"", value)
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("odatagen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	headers := headerFlag{}
+	var include, exclude listFlag
+	apiUrl := flags.String("url", "", "root `url` of the OData service, $metadata is fetched from it")
+	metadataFile := flags.String("file", "", "local EDMX `file` to generate the code from instead of fetching $metadata, - reads standard input")
+	saveMetadata := flags.String("save-metadata", "", "`name` of a file in the output directory where the EDMX document is saved")
+	directory := flags.String("out", ".", "output `directory`")
+	packageName := flags.String("package", os.Getenv("GOPACKAGE"), "package `name` of the generated code, defaults to the name of the output directory")
+	fileName := flags.String("filename", "modelDefinitions.go", "`name` of the generated file")
+	bearerToken := flags.String("bearer", "", "bearer `token` sent as Authorization header when fetching $metadata")
+	referenceDirectory := flags.String("references", "", "`directory` where documents referenced by the EDMX document are cached")
+	configFile := flags.String("config", "", "YAML or JSON `file` with renames, type overrides and other options of the generated code")
+	check := flags.Bool("check", false, "only check that the generated code is up to date, exits with status 1 if not and 2 on errors")
+	flags.Var(headers, "header", "HTTP `header` \"Name: Value\" sent when fetching $metadata, can be repeated")
+	flags.Var(&include, "include", "comma separated `patterns` of entity sets, singletons, operation imports and types to generate")
+	flags.Var(&exclude, "exclude", "comma separated `patterns` of entity sets, singletons, operations and types to leave out")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: odatagen (-url url | -file file) [flags]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if (*apiUrl == "") == (*metadataFile == "") {
+		_, _ = fmt.Fprintln(stderr, "odatagen: exactly one of -url and -file must be given")
+		flags.Usage()
+		return 2
+	}
+	if *bearerToken != "" {
+		headers["Authorization"] = "Bearer " + *bearerToken
+	}
+
+	generator := modelGenerator.Generator{
+		ApiUrl:             *apiUrl,
+		MetadataFile:       *metadataFile,
+		SaveMetadataFile:   *saveMetadata,
+		DirectoryPath:      *directory,
+		PackageName:        *packageName,
+		FileName:           *fileName,
+		Headers:            headers,
+		Include:            include,
+		Exclude:            exclude,
+		ReferenceDirectory: *referenceDirectory,
+		ConfigFile:         *configFile,
+	}
+	if *metadataFile == "-" {
+		generator.MetadataFile = ""
+		generator.MetadataReader = stdin
+	}
+
+	if *check {
+		err := generator.CheckCode()
+		if errors.Is(err, modelGenerator.ErrCodeOutdated) {
+			_, _ = fmt.Fprintf(stderr, "odatagen: %s, run odatagen without -check to regenerate it\n", err)
+			return 1
+		} else if err != nil {
+			_, _ = fmt.Fprintf(stderr, "odatagen: %s\n", err)
+			return 2
+		}
+		_, _ = fmt.Fprintln(stdout, "generated code is up to date")
+		return 0
+	}
+
+	if err := generator.GenerateCode(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "odatagen: error while generating code: %s\n", err)
+		return 1
+	}
+	_, _ = fmt.Fprintln(stdout, "code generated successfully")
+	return 0
+}
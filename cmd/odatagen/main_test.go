@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testEdmx = `<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EntityType Name="Order">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+</EntityType>
+<EntityType Name="Customer">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+</EntityType>
+<EntityContainer Name="Container">
+<EntitySet Name="Orders" EntityType="Shop.Order"/>
+<EntitySet Name="Customers" EntityType="Shop.Customer"/>
+</EntityContainer>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`
+
+func TestRun(t *testing.T) {
+	directory := t.TempDir()
+	metadataFile := filepath.Join(directory, "metadata.xml")
+	assert.NoError(t, os.WriteFile(metadataFile, []byte(testEdmx), 0644))
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-file", metadataFile, "-out", directory, "-package", "shop", "-filename", "shop.go", "-exclude", "Customers,Shop.Customer"}
+	assert.Equal(t, 0, run(args, nil, &stdout, &stderr), stderr.String())
+
+	code, err := os.ReadFile(filepath.Join(directory, "shop.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), "package shop\n")
+	assert.Contains(t, string(code), "func NewOrderCollection(")
+	assert.NotContains(t, string(code), "Customer")
+
+	assert.Equal(t, 0, run(append(args, "-check"), nil, &stdout, &stderr), stderr.String())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(directory, "shop.go"), append(code, []byte("\n// changed\n")...), 0644))
+	stderr.Reset()
+	assert.Equal(t, 1, run(append(args, "--check"), nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "generated code is out of date")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run(append(args, "-check", "-config", filepath.Join(directory, "missing.yaml")), nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "missing.yaml")
+}
+
+func TestRun_stdin(t *testing.T) {
+	directory := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-file", "-", "-out", directory, "-package", "shop", "-save-metadata", "metadata.xml"}
+	assert.Equal(t, 0, run(args, strings.NewReader(testEdmx), &stdout, &stderr), stderr.String())
+
+	savedMetadata, err := os.ReadFile(filepath.Join(directory, "metadata.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, testEdmx, string(savedMetadata))
+
+	args = []string{"-file", filepath.Join(directory, "metadata.xml"), "-out", directory, "-package", "shop", "-check"}
+	assert.Equal(t, 0, run(args, nil, &stdout, &stderr), stderr.String())
+}
+
+func TestRun_config(t *testing.T) {
+	directory := t.TempDir()
+	metadataFile := filepath.Join(directory, "metadata.xml")
+	configFile := filepath.Join(directory, "odatagen.yaml")
+	assert.NoError(t, os.WriteFile(metadataFile, []byte(testEdmx), 0644))
+	assert.NoError(t, os.WriteFile(configFile, []byte("package: shop\ninclude: [Orders]\ntypes:\n  Shop.Order: {name: PurchaseOrder}\n"), 0644))
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-file", metadataFile, "-out", directory, "-package", "", "-config", configFile}
+	assert.Equal(t, 0, run(args, nil, &stdout, &stderr), stderr.String())
+
+	code, err := os.ReadFile(filepath.Join(directory, "modelDefinitions.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), "package shop\n")
+	assert.Contains(t, string(code), "type PurchaseOrder struct")
+	assert.NotContains(t, string(code), "Customer")
+}
+
+func TestRun_invalidArguments(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run([]string{}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "exactly one of -url and -file must be given")
+
+	assert.Equal(t, 2, run([]string{"-file", "metadata.xml", "-header", "Authorization"}, nil, &stdout, &stderr))
+}
+
+func TestHeaderFlag(t *testing.T) {
+	headers := headerFlag{}
+	assert.NoError(t, headers.Set("Authorization: Bearer abc:def"))
+	assert.NoError(t, headers.Set("X-Tenant:42"))
+	assert.Equal(t, headerFlag{"Authorization": "Bearer abc:def", "X-Tenant": "42"}, headers)
+}
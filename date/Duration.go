@@ -0,0 +1,151 @@
+package date
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a signed duration of days, hours, minutes and seconds, formatted as ISO 8601 like P1DT2H30M
+type Duration time.Duration
+
+// ParseDuration parses an ISO 8601 day-time duration like P1DT2H30M15.5S or -PT45M
+func ParseDuration(value string) (Duration, error) {
+	rest := value
+	negative := strings.HasPrefix(rest, "-")
+	rest = strings.TrimPrefix(rest, "-")
+	if !strings.HasPrefix(rest, "P") || len(rest) == 1 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	rest = rest[1:]
+
+	var duration time.Duration
+	inTime := false
+	units := "D"
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime || len(rest) == 1 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			inTime = true
+			units = "HMS"
+			rest = rest[1:]
+			continue
+		}
+		end := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if end <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		unit := strings.IndexByte(units, rest[end])
+		if unit < 0 {
+			return 0, fmt.Errorf("invalid duration %q, only days, hours, minutes and seconds are supported", value)
+		}
+		number := rest[:end]
+		switch units[unit] {
+		case 'D':
+			days, err := strconv.ParseInt(number, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid days in duration %q", value)
+			}
+			duration += time.Duration(days) * 24 * time.Hour
+		case 'H', 'M':
+			amount, err := strconv.ParseInt(number, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			if units[unit] == 'H' {
+				duration += time.Duration(amount) * time.Hour
+			} else {
+				duration += time.Duration(amount) * time.Minute
+			}
+		case 'S':
+			seconds, fraction := number, ""
+			if dot := strings.IndexByte(number, '.'); dot >= 0 {
+				seconds, fraction = number[:dot], number[dot+1:]
+			}
+			wholeSeconds, err := strconv.ParseInt(seconds, 10, 64)
+			if err != nil || len(fraction) > 9 || strings.Contains(fraction, ".") {
+				return 0, fmt.Errorf("invalid seconds in duration %q", value)
+			}
+			duration += time.Duration(wholeSeconds) * time.Second
+			if fraction != "" {
+				nanoseconds, err := strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+				if err != nil {
+					return 0, fmt.Errorf("invalid seconds in duration %q", value)
+				}
+				duration += time.Duration(nanoseconds)
+			}
+		}
+		units = units[unit+1:]
+		rest = rest[end+1:]
+	}
+
+	if negative {
+		duration = -duration
+	}
+	return Duration(duration), nil
+}
+
+// Duration returns the duration as a time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats the duration as ISO 8601, e.g. P1DT2H30M or PT0S
+func (d Duration) String() string {
+	duration := time.Duration(d)
+	value := "P"
+	if duration < 0 {
+		value = "-P"
+		duration = -duration
+	}
+	if days := duration / (24 * time.Hour); days > 0 {
+		value += strconv.FormatInt(int64(days), 10) + "D"
+		duration -= days * 24 * time.Hour
+	}
+	if duration == 0 && value != "P" && value != "-P" {
+		return value
+	}
+
+	value += "T"
+	if hours := duration / time.Hour; hours > 0 {
+		value += strconv.FormatInt(int64(hours), 10) + "H"
+		duration -= hours * time.Hour
+	}
+	if minutes := duration / time.Minute; minutes > 0 {
+		value += strconv.FormatInt(int64(minutes), 10) + "M"
+		duration -= minutes * time.Minute
+	}
+	if duration > 0 || strings.HasSuffix(value, "T") {
+		value += strconv.FormatInt(int64(duration/time.Second), 10)
+		if nanoseconds := duration % time.Second; nanoseconds > 0 {
+			value += strings.TrimRight(fmt.Sprintf(".%09d", nanoseconds), "0")
+		}
+		value += "S"
+	}
+	return value
+}
+
+// ODataLiteral formats the duration as an OData URL literal, e.g. duration'PT1H'
+func (d Duration) ODataLiteral() string {
+	return "duration'" + d.String() + "'"
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	var err error
+	*d, err = ParseDuration(value)
+	return err
+}
@@ -0,0 +1,112 @@
+package date
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeOfDay represents a time of the day without a date or timezone, with up to nanosecond precision
+type TimeOfDay struct {
+	sinceMidnight time.Duration
+}
+
+func NewTimeOfDay(hour int, minute int, second int, nanosecond int) TimeOfDay {
+	return TimeOfDay{time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(nanosecond)}
+}
+
+// TimeOfDayOf returns the time of the day of the time
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return NewTimeOfDay(t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+}
+
+// ParseTimeOfDay parses a time of the day like 08:30, 08:30:15 or 08:30:15.250
+func ParseTimeOfDay(value string) (TimeOfDay, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return TimeOfDay{}, fmt.Errorf("invalid time of day %q", value)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 || len(parts[0]) != 2 {
+		return TimeOfDay{}, fmt.Errorf("invalid hour in time of day %q", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 || len(parts[1]) != 2 {
+		return TimeOfDay{}, fmt.Errorf("invalid minute in time of day %q", value)
+	}
+	second, nanosecond := 0, 0
+	if len(parts) == 3 {
+		seconds := parts[2]
+		fraction := ""
+		if dot := strings.Index(seconds, "."); dot >= 0 {
+			seconds, fraction = seconds[:dot], seconds[dot+1:]
+			if len(fraction) == 0 || len(fraction) > 9 {
+				return TimeOfDay{}, fmt.Errorf("invalid fractional seconds in time of day %q", value)
+			}
+			if nanosecond, err = strconv.Atoi(fraction + strings.Repeat("0", 9-len(fraction))); err != nil || nanosecond < 0 {
+				return TimeOfDay{}, fmt.Errorf("invalid fractional seconds in time of day %q", value)
+			}
+		}
+		second, err = strconv.Atoi(seconds)
+		if err != nil || second < 0 || second > 59 || len(seconds) != 2 {
+			return TimeOfDay{}, fmt.Errorf("invalid second in time of day %q", value)
+		}
+	}
+	return NewTimeOfDay(hour, minute, second, nanosecond), nil
+}
+
+func (t TimeOfDay) Hour() int {
+	return int(t.sinceMidnight / time.Hour)
+}
+
+func (t TimeOfDay) Minute() int {
+	return int(t.sinceMidnight % time.Hour / time.Minute)
+}
+
+func (t TimeOfDay) Second() int {
+	return int(t.sinceMidnight % time.Minute / time.Second)
+}
+
+func (t TimeOfDay) Nanosecond() int {
+	return int(t.sinceMidnight % time.Second)
+}
+
+// On returns the time of the day on the date in the location
+func (t TimeOfDay) On(date Date, location *time.Location) time.Time {
+	year, month, day := date.time.Date()
+	return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+}
+
+// String formats the time of day as 08:30:15, with fractional seconds only when there are any
+func (t TimeOfDay) String() string {
+	value := fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+	if nanosecond := t.Nanosecond(); nanosecond != 0 {
+		value += strings.TrimRight(fmt.Sprintf(".%09d", nanosecond), "0")
+	}
+	return value
+}
+
+// ODataLiteral formats the time of day as an OData URL literal
+func (t TimeOfDay) ODataLiteral() string {
+	return t.String()
+}
+
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	var err error
+	*t, err = ParseTimeOfDay(value)
+	return err
+}
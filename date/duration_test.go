@@ -0,0 +1,50 @@
+package date
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_Parse_duration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"P1DT2H30M":        26*time.Hour + 30*time.Minute,
+		"PT15.5S":          15*time.Second + 500*time.Millisecond,
+		"-PT45M":           -45 * time.Minute,
+		"P2D":              48 * time.Hour,
+		"PT0S":             0,
+		"PT1H0.000000001S": time.Hour + time.Nanosecond,
+	}
+	for value, expected := range tests {
+		d, err := ParseDuration(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, d.Duration(), value)
+	}
+
+	for _, invalid := range []string{"", "P", "PT", "P1DT", "1D", "P1Y", "P1H", "PT1S2M", "PT1.5M", "P-1D"} {
+		_, err := ParseDuration(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func Test_Duration_to_string(t *testing.T) {
+	assert.Equal(t, "P1DT2H30M", Duration(26*time.Hour+30*time.Minute).String())
+	assert.Equal(t, "P2D", Duration(48*time.Hour).String())
+	assert.Equal(t, "PT0S", Duration(0).String())
+	assert.Equal(t, "-PT1M0.25S", Duration(-time.Minute-250*time.Millisecond).String())
+	assert.Equal(t, "duration'PT1H'", Duration(time.Hour).ODataLiteral())
+}
+
+func Test_Duration_json(t *testing.T) {
+	type res struct {
+		Duration Duration `json:"duration"`
+	}
+	jsonData, err := json.Marshal(res{Duration(90 * time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"duration":"PT1H30M"}`, string(jsonData))
+
+	var data res
+	assert.NoError(t, json.Unmarshal([]byte(`{"duration":"P1DT12H"}`), &data))
+	assert.Equal(t, 36*time.Hour, data.Duration.Duration())
+}
@@ -0,0 +1,45 @@
+package date
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_Parse_time_of_day(t *testing.T) {
+	tod, err := ParseTimeOfDay("08:30:15.25")
+	assert.NoError(t, err)
+	assert.Equal(t, NewTimeOfDay(8, 30, 15, 250000000), tod)
+	assert.Equal(t, "08:30:15.25", tod.String())
+	assert.Equal(t, []int{8, 30, 15, 250000000}, []int{tod.Hour(), tod.Minute(), tod.Second(), tod.Nanosecond()})
+
+	tod, err = ParseTimeOfDay("23:05")
+	assert.NoError(t, err)
+	assert.Equal(t, "23:05:00", tod.String())
+
+	for _, invalid := range []string{"", "8:30", "24:00:00", "12:60", "12:00:00.", "12:00:00.1234567890", "12:00:00:00"} {
+		_, err = ParseTimeOfDay(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func Test_Time_of_day_json(t *testing.T) {
+	type res struct {
+		StartsAt TimeOfDay `json:"startsAt"`
+	}
+	jsonData, err := json.Marshal(res{NewTimeOfDay(14, 0, 5, 0)})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"startsAt":"14:00:05"}`, string(jsonData))
+
+	var data res
+	assert.NoError(t, json.Unmarshal([]byte(`{"startsAt":"07:45:00.5"}`), &data))
+	assert.Equal(t, NewTimeOfDay(7, 45, 0, 500000000), data.StartsAt)
+	assert.Error(t, json.Unmarshal([]byte(`{"startsAt":"late"}`), &data))
+}
+
+func Test_Time_of_day_on_date(t *testing.T) {
+	tod := TimeOfDayOf(time.Date(2021, 1, 1, 9, 15, 0, 0, time.UTC))
+	assert.Equal(t, "09:15:00", tod.ODataLiteral())
+	assert.Equal(t, time.Date(2021, 10, 11, 9, 15, 0, 0, time.UTC), tod.On(New(2021, 10, 11), time.UTC))
+}
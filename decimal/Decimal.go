@@ -0,0 +1,178 @@
+package decimal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal represents an Edm.Decimal value exactly, with an arbitrary number of digits.
+// The value is unscaled * 10^-scale, the zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+var ten = big.NewInt(10)
+
+// New returns unscaled * 10^-scale, e.g. New(1234, 2) is 12.34
+func New(unscaled int64, scale int32) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// NewFromFloat returns the decimal with the shortest representation of the float
+func NewFromFloat(value float64) Decimal {
+	d, _ := Parse(strconv.FormatFloat(value, 'f', -1, 64))
+	return d
+}
+
+// Parse parses a decimal number like -12.340 or 1.5E+3
+func Parse(value string) (Decimal, error) {
+	mantissa, exponent := value, int64(0)
+	if e := strings.IndexAny(value, "eE"); e >= 0 {
+		var err error
+		mantissa = value[:e]
+		if exponent, err = strconv.ParseInt(value[e+1:], 10, 32); err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+		}
+	}
+	digits, fraction := mantissa, ""
+	if dot := strings.IndexByte(mantissa, '.'); dot >= 0 {
+		digits, fraction = mantissa[:dot], mantissa[dot+1:]
+	}
+	sign := ""
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	if digits+fraction == "" || strings.Trim(digits+fraction, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", value)
+	}
+
+	unscaled, _ := new(big.Int).SetString(sign+digits+fraction, 10)
+	scale := int64(len(fraction)) - exponent
+	if scale < 0 {
+		unscaled.Mul(unscaled, new(big.Int).Exp(ten, big.NewInt(-scale), nil))
+		scale = 0
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+// MustParse parses the decimal, and panics if it is invalid
+func MustParse(value string) Decimal {
+	d, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) unscaledValue() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Precision returns the number of significant digits, including the digits after the decimal point
+func (d Decimal) Precision() int {
+	digits := new(big.Int).Abs(d.unscaledValue()).String()
+	if len(digits) < int(d.scale) {
+		return int(d.scale)
+	}
+	return len(digits)
+}
+
+// Round rounds the decimal half away from zero to the scale
+func (d Decimal) Round(scale int32) Decimal {
+	unscaled := d.unscaledValue()
+	if scale >= d.scale {
+		factor := new(big.Int).Exp(ten, big.NewInt(int64(scale-d.scale)), nil)
+		return Decimal{unscaled: new(big.Int).Mul(unscaled, factor), scale: scale}
+	}
+	divisor := new(big.Int).Exp(ten, big.NewInt(int64(d.scale-scale)), nil)
+	quotient, remainder := new(big.Int).QuoRem(new(big.Int).Abs(unscaled), divisor, new(big.Int))
+	if remainder.Mul(remainder, big.NewInt(2)).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if unscaled.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	return Decimal{unscaled: quotient, scale: scale}
+}
+
+// Fits returns true if the decimal can be stored with the precision and scale of an Edm.Decimal property
+// without rounding
+func (d Decimal) Fits(precision int, scale int32) bool {
+	if d.Round(scale).Cmp(d) != 0 {
+		return false
+	}
+	return d.Round(scale).Precision() <= precision
+}
+
+// Cmp returns -1, 0 or 1 when the decimal is less than, equal to or greater than the other decimal
+func (d Decimal) Cmp(other Decimal) int {
+	scale := d.scale
+	if other.scale > scale {
+		scale = other.scale
+	}
+	return d.Round(scale).unscaled.Cmp(other.Round(scale).unscaled)
+}
+
+// Equal returns true if the decimals have the same value, regardless of their scale
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.unscaledValue()).String()
+	sign := ""
+	if d.unscaledValue().Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale <= 0 {
+		return sign + digits + strings.Repeat("0", int(-d.scale))
+	}
+	if len(digits) <= int(d.scale) {
+		digits = strings.Repeat("0", int(d.scale)-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+}
+
+// ODataLiteral formats the decimal as an OData URL literal
+func (d Decimal) ODataLiteral() string {
+	return d.String()
+}
+
+// MarshalJSON writes the decimal as a JSON number with all its digits
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON reads the decimal from a JSON number, or from a string as sent by services
+// with IEEE754Compatible=true
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value := string(data)
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+	var err error
+	*d, err = Parse(value)
+	return err
+}
@@ -0,0 +1,79 @@
+package decimal
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := map[string]string{
+		"12.340":                           "12.340",
+		"-0.05":                            "-0.05",
+		"+7":                               "7",
+		"1.5E+3":                           "1500",
+		"1.5e-3":                           "0.0015",
+		".5":                               "0.5",
+		"79228162514264337593543950335.01": "79228162514264337593543950335.01",
+	}
+	for value, expected := range tests {
+		d, err := Parse(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, d.String(), value)
+	}
+
+	for _, invalid := range []string{"", "-", "1.2.3", "abc", "1e", "1,5"} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func Test_New(t *testing.T) {
+	assert.Equal(t, "12.34", New(1234, 2).String())
+	assert.Equal(t, "-0.001", New(-1, 3).String())
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.Equal(t, "0.1", NewFromFloat(0.1).String())
+	assert.Equal(t, 12.34, New(1234, 2).Float64())
+}
+
+func Test_Round(t *testing.T) {
+	assert.Equal(t, "12.35", MustParse("12.345").Round(2).String())
+	assert.Equal(t, "-12.35", MustParse("-12.345").Round(2).String())
+	assert.Equal(t, "12.34", MustParse("12.344").Round(2).String())
+	assert.Equal(t, "12.3000", MustParse("12.3").Round(4).String())
+	assert.Equal(t, "13", MustParse("12.5").Round(0).String())
+}
+
+func Test_Precision_and_scale(t *testing.T) {
+	d := MustParse("123.45")
+	assert.Equal(t, 5, d.Precision())
+	assert.Equal(t, int32(2), d.Scale())
+	assert.Equal(t, 2, MustParse("0.05").Precision())
+	assert.True(t, d.Fits(5, 2))
+	assert.True(t, d.Fits(6, 3))
+	assert.False(t, d.Fits(4, 2))
+	assert.False(t, d.Fits(10, 1))
+}
+
+func Test_Cmp(t *testing.T) {
+	assert.True(t, MustParse("1.50").Equal(MustParse("1.5")))
+	assert.Equal(t, -1, MustParse("1.49").Cmp(MustParse("1.5")))
+	assert.Equal(t, 1, MustParse("-1").Cmp(MustParse("-1.01")))
+	assert.True(t, Decimal{}.Equal(MustParse("0.00")))
+}
+
+func Test_Json(t *testing.T) {
+	type res struct {
+		Price Decimal `json:"price"`
+	}
+	jsonData, err := json.Marshal(res{MustParse("79228162514264337593543950335.99")})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"price":79228162514264337593543950335.99}`, string(jsonData))
+
+	var data res
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":12.50}`), &data))
+	assert.Equal(t, "12.50", data.Price.String())
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":"0.1"}`), &data))
+	assert.Equal(t, "0.1", data.Price.ODataLiteral())
+	assert.Error(t, json.Unmarshal([]byte(`{"price":"free"}`), &data))
+}
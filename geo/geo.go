@@ -0,0 +1,180 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Space is either Geography, for coordinates on the round earth, or Geometry, for coordinates in a flat plane
+type Space interface {
+	literalPrefix() string
+	defaultSRID() int
+}
+
+// Geography is the space of Edm.Geography values, with longitude and latitude in degrees and SRID 4326 by default
+type Geography struct{}
+
+func (Geography) literalPrefix() string {
+	return "geography"
+}
+
+func (Geography) defaultSRID() int {
+	return 4326
+}
+
+// Geometry is the space of Edm.Geometry values, with coordinates in a flat plane and SRID 0 by default
+type Geometry struct{}
+
+func (Geometry) literalPrefix() string {
+	return "geometry"
+}
+
+func (Geometry) defaultSRID() int {
+	return 0
+}
+
+// Position is a longitude and latitude, or x and y, optionally followed by the altitude and measure
+type Position []float64
+
+// Shape is implemented by all spatial values of the space
+type Shape[S Space] interface {
+	ODataLiteral() string
+	MarshalJSON() ([]byte, error)
+	wellKnownText() string
+	space() S
+}
+
+func formatPosition(position Position) string {
+	coordinates := make([]string, len(position))
+	for i, coordinate := range position {
+		coordinates[i] = strconv.FormatFloat(coordinate, 'f', -1, 64)
+	}
+	return strings.Join(coordinates, " ")
+}
+
+func formatPositions(positions []Position) string {
+	formatted := make([]string, len(positions))
+	for i, position := range positions {
+		formatted[i] = formatPosition(position)
+	}
+	return "(" + strings.Join(formatted, ",") + ")"
+}
+
+func formatRings(rings [][]Position) string {
+	formatted := make([]string, len(rings))
+	for i, ring := range rings {
+		formatted[i] = formatPositions(ring)
+	}
+	return "(" + strings.Join(formatted, ",") + ")"
+}
+
+// literal formats the well known text as an OData literal like geography'SRID=4326;POINT(4.9 52.4)'
+func literal[S Space](srid int, wellKnownText string) string {
+	var space S
+	if srid == 0 {
+		srid = space.defaultSRID()
+	}
+	return fmt.Sprintf("%s'SRID=%d;%s'", space.literalPrefix(), srid, wellKnownText)
+}
+
+type geoJSONCrs struct {
+	Type       string `json:"type"`
+	Properties struct {
+		Name string `json:"name"`
+	} `json:"properties"`
+}
+
+type geoJSON struct {
+	Type        string            `json:"type"`
+	Coordinates json.RawMessage   `json:"coordinates,omitempty"`
+	Geometries  []json.RawMessage `json:"geometries,omitempty"`
+	Crs         *geoJSONCrs       `json:"crs,omitempty"`
+}
+
+// crs returns the coordinate reference system of the GeoJSON value, which is left out for the default SRID
+func crs[S Space](srid int) *geoJSONCrs {
+	var space S
+	if srid == 0 || srid == space.defaultSRID() {
+		return nil
+	}
+	value := &geoJSONCrs{Type: "name"}
+	value.Properties.Name = "EPSG:" + strconv.Itoa(srid)
+	return value
+}
+
+func marshalGeoJSON[S Space](geoJSONType string, coordinates interface{}, srid int) ([]byte, error) {
+	coordinatesData, err := json.Marshal(coordinates)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSON{Type: geoJSONType, Coordinates: coordinatesData, Crs: crs[S](srid)})
+}
+
+func parseGeoJSON(data []byte, geoJSONType string) (geoJSON, int, error) {
+	var value geoJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, 0, err
+	}
+	if value.Type != geoJSONType {
+		return value, 0, fmt.Errorf("expected GeoJSON %s, got %q", geoJSONType, value.Type)
+	}
+	srid := 0
+	if value.Crs != nil {
+		name := value.Crs.Properties.Name
+		var err error
+		if srid, err = strconv.Atoi(name[strings.LastIndex(name, ":")+1:]); err != nil {
+			return value, 0, fmt.Errorf("unsupported GeoJSON crs %q", name)
+		}
+	}
+	return value, srid, nil
+}
+
+func unmarshalGeoJSON(data []byte, geoJSONType string, coordinates interface{}, srid *int) error {
+	value, parsedSrid, err := parseGeoJSON(data, geoJSONType)
+	if err != nil {
+		return err
+	}
+	*srid = parsedSrid
+	return json.Unmarshal(value.Coordinates, coordinates)
+}
+
+// decodeShape decodes the GeoJSON value into the shape given by its type
+func decodeShape[S Space](data []byte) (Shape[S], error) {
+	var value geoJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	switch value.Type {
+	case "Point":
+		var shape Point[S]
+		err := shape.UnmarshalJSON(data)
+		return shape, err
+	case "LineString":
+		var shape LineString[S]
+		err := shape.UnmarshalJSON(data)
+		return shape, err
+	case "Polygon":
+		var shape Polygon[S]
+		err := shape.UnmarshalJSON(data)
+		return shape, err
+	case "MultiPoint":
+		var shape MultiPoint[S]
+		err := shape.UnmarshalJSON(data)
+		return shape, err
+	case "MultiLineString":
+		var shape MultiLineString[S]
+		err := shape.UnmarshalJSON(data)
+		return shape, err
+	case "MultiPolygon":
+		var shape MultiPolygon[S]
+		err := shape.UnmarshalJSON(data)
+		return shape, err
+	case "GeometryCollection":
+		var shape Collection[S]
+		err := shape.UnmarshalJSON(data)
+		return shape, err
+	}
+	return nil, fmt.Errorf("unsupported GeoJSON type %q", value.Type)
+}
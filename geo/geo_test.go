@@ -0,0 +1,88 @@
+package geo
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Literals(t *testing.T) {
+	assert.Equal(t, "geography'SRID=4326;POINT(-122.1 47.6)'", NewGeographyPoint(-122.1, 47.6).ODataLiteral())
+	assert.Equal(t, "geometry'SRID=0;POINT(1 2)'", NewGeometryPoint(1, 2).ODataLiteral())
+	assert.Equal(t, "geometry'SRID=3857;POINT(1 2 3)'", Point[Geometry]{Position: Position{1, 2, 3}, SRID: 3857}.ODataLiteral())
+	assert.Equal(t, "geography'SRID=4326;LINESTRING(0 0,1 1.5)'",
+		LineString[Geography]{Positions: []Position{{0, 0}, {1, 1.5}}}.ODataLiteral())
+	assert.Equal(t, "geography'SRID=4326;POLYGON((0 0,4 0,4 4,0 0),(1 1,2 1,2 2,1 1))'",
+		Polygon[Geography]{Rings: [][]Position{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}}}.ODataLiteral())
+	assert.Equal(t, "geography'SRID=4326;MULTIPOINT((0 0),(1 1))'",
+		MultiPoint[Geography]{Positions: []Position{{0, 0}, {1, 1}}}.ODataLiteral())
+	assert.Equal(t, "geography'SRID=4326;MULTILINESTRING((0 0,1 1),(2 2,3 3))'",
+		MultiLineString[Geography]{LineStrings: [][]Position{{{0, 0}, {1, 1}}, {{2, 2}, {3, 3}}}}.ODataLiteral())
+	assert.Equal(t, "geography'SRID=4326;MULTIPOLYGON(((0 0,1 0,1 1,0 0)),((5 5,6 5,6 6,5 5)))'",
+		MultiPolygon[Geography]{Polygons: [][][]Position{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{5, 5}, {6, 5}, {6, 6}, {5, 5}}}}}.ODataLiteral())
+	assert.Equal(t, "geography'SRID=4326;COLLECTION(POINT(1 2),LINESTRING(0 0,1 1))'",
+		Collection[Geography]{Shapes: []Shape[Geography]{NewGeographyPoint(1, 2), LineString[Geography]{Positions: []Position{{0, 0}, {1, 1}}}}}.ODataLiteral())
+	assert.Equal(t, "geography'SRID=4326;POINT(1 2)'", Any[Geography]{Shape: NewGeographyPoint(1, 2)}.ODataLiteral())
+}
+
+func Test_Point_json(t *testing.T) {
+	type location struct {
+		Loc Point[Geography] `json:"Loc"`
+	}
+	jsonData, err := json.Marshal(location{NewGeographyPoint(-122.1, 47.6)})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Loc":{"type":"Point","coordinates":[-122.1,47.6]}}`, string(jsonData))
+
+	var data location
+	assert.NoError(t, json.Unmarshal(jsonData, &data))
+	assert.Equal(t, NewGeographyPoint(-122.1, 47.6), data.Loc)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"Loc":{"type":"Point","coordinates":[1,2],"crs":{"type":"name","properties":{"name":"EPSG:3857"}}}}`), &data))
+	assert.Equal(t, Point[Geography]{Position: Position{1, 2}, SRID: 3857}, data.Loc)
+	jsonData, err = json.Marshal(data.Loc)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"Point","coordinates":[1,2],"crs":{"type":"name","properties":{"name":"EPSG:3857"}}}`, string(jsonData))
+
+	assert.EqualError(t, json.Unmarshal([]byte(`{"Loc":{"type":"LineString","coordinates":[[1,2]]}}`), &data), `expected GeoJSON Point, got "LineString"`)
+}
+
+func Test_Shapes_json(t *testing.T) {
+	polygon := Polygon[Geometry]{Rings: [][]Position{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}}
+	jsonData, err := json.Marshal(polygon)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`, string(jsonData))
+	var decodedPolygon Polygon[Geometry]
+	assert.NoError(t, json.Unmarshal(jsonData, &decodedPolygon))
+	assert.Equal(t, polygon, decodedPolygon)
+
+	multiPolygon := MultiPolygon[Geometry]{Polygons: [][][]Position{polygon.Rings}}
+	jsonData, err = json.Marshal(multiPolygon)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]]]}`, string(jsonData))
+	var decodedMultiPolygon MultiPolygon[Geometry]
+	assert.NoError(t, json.Unmarshal(jsonData, &decodedMultiPolygon))
+	assert.Equal(t, multiPolygon, decodedMultiPolygon)
+}
+
+func Test_Collection_json(t *testing.T) {
+	collection := Collection[Geography]{Shapes: []Shape[Geography]{
+		NewGeographyPoint(1, 2),
+		MultiLineString[Geography]{LineStrings: [][]Position{{{0, 0}, {1, 1}}}},
+	}}
+	jsonData, err := json.Marshal(collection)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"MultiLineString","coordinates":[[[0,0],[1,1]]]}]}`, string(jsonData))
+
+	var decoded Collection[Geography]
+	assert.NoError(t, json.Unmarshal(jsonData, &decoded))
+	assert.Equal(t, collection, decoded)
+
+	var anyShape Any[Geography]
+	assert.NoError(t, json.Unmarshal([]byte(`{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`), &anyShape))
+	assert.Equal(t, MultiPoint[Geography]{Positions: []Position{{1, 2}, {3, 4}}}, anyShape.Shape)
+	jsonData, err = json.Marshal(anyShape)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"MultiPoint","coordinates":[[1,2],[3,4]]}`, string(jsonData))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"type":"Circle"}`), &anyShape))
+}
@@ -0,0 +1,284 @@
+package geo
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Point is a single position, e.g. Point[Geography] for Edm.GeographyPoint
+type Point[S Space] struct {
+	Position Position
+	// SRID of the coordinate reference system, 0 uses the default of the space
+	SRID int
+}
+
+// NewGeographyPoint returns the geography point at the longitude and latitude
+func NewGeographyPoint(longitude float64, latitude float64) Point[Geography] {
+	return Point[Geography]{Position: Position{longitude, latitude}}
+}
+
+// NewGeometryPoint returns the geometry point at x and y
+func NewGeometryPoint(x float64, y float64) Point[Geometry] {
+	return Point[Geometry]{Position: Position{x, y}}
+}
+
+func (p Point[S]) wellKnownText() string {
+	return "POINT(" + formatPosition(p.Position) + ")"
+}
+
+func (p Point[S]) space() S {
+	var space S
+	return space
+}
+
+func (p Point[S]) ODataLiteral() string {
+	return literal[S](p.SRID, p.wellKnownText())
+}
+
+func (p Point[S]) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON[S]("Point", p.Position, p.SRID)
+}
+
+func (p *Point[S]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return unmarshalGeoJSON(data, "Point", &p.Position, &p.SRID)
+}
+
+// LineString is a line through the positions
+type LineString[S Space] struct {
+	Positions []Position
+	SRID      int
+}
+
+func (l LineString[S]) wellKnownText() string {
+	return "LINESTRING" + formatPositions(l.Positions)
+}
+
+func (l LineString[S]) space() S {
+	var space S
+	return space
+}
+
+func (l LineString[S]) ODataLiteral() string {
+	return literal[S](l.SRID, l.wellKnownText())
+}
+
+func (l LineString[S]) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON[S]("LineString", l.Positions, l.SRID)
+}
+
+func (l *LineString[S]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return unmarshalGeoJSON(data, "LineString", &l.Positions, &l.SRID)
+}
+
+// Polygon is an area given by closed rings of positions, the first ring is the outer boundary and
+// the following rings are holes
+type Polygon[S Space] struct {
+	Rings [][]Position
+	SRID  int
+}
+
+func (p Polygon[S]) wellKnownText() string {
+	return "POLYGON" + formatRings(p.Rings)
+}
+
+func (p Polygon[S]) space() S {
+	var space S
+	return space
+}
+
+func (p Polygon[S]) ODataLiteral() string {
+	return literal[S](p.SRID, p.wellKnownText())
+}
+
+func (p Polygon[S]) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON[S]("Polygon", p.Rings, p.SRID)
+}
+
+func (p *Polygon[S]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return unmarshalGeoJSON(data, "Polygon", &p.Rings, &p.SRID)
+}
+
+type MultiPoint[S Space] struct {
+	Positions []Position
+	SRID      int
+}
+
+func (m MultiPoint[S]) wellKnownText() string {
+	points := make([]string, len(m.Positions))
+	for i, position := range m.Positions {
+		points[i] = "(" + formatPosition(position) + ")"
+	}
+	return "MULTIPOINT(" + strings.Join(points, ",") + ")"
+}
+
+func (m MultiPoint[S]) space() S {
+	var space S
+	return space
+}
+
+func (m MultiPoint[S]) ODataLiteral() string {
+	return literal[S](m.SRID, m.wellKnownText())
+}
+
+func (m MultiPoint[S]) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON[S]("MultiPoint", m.Positions, m.SRID)
+}
+
+func (m *MultiPoint[S]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return unmarshalGeoJSON(data, "MultiPoint", &m.Positions, &m.SRID)
+}
+
+type MultiLineString[S Space] struct {
+	LineStrings [][]Position
+	SRID        int
+}
+
+func (m MultiLineString[S]) wellKnownText() string {
+	return "MULTILINESTRING" + formatRings(m.LineStrings)
+}
+
+func (m MultiLineString[S]) space() S {
+	var space S
+	return space
+}
+
+func (m MultiLineString[S]) ODataLiteral() string {
+	return literal[S](m.SRID, m.wellKnownText())
+}
+
+func (m MultiLineString[S]) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON[S]("MultiLineString", m.LineStrings, m.SRID)
+}
+
+func (m *MultiLineString[S]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return unmarshalGeoJSON(data, "MultiLineString", &m.LineStrings, &m.SRID)
+}
+
+type MultiPolygon[S Space] struct {
+	Polygons [][][]Position
+	SRID     int
+}
+
+func (m MultiPolygon[S]) wellKnownText() string {
+	polygons := make([]string, len(m.Polygons))
+	for i, rings := range m.Polygons {
+		polygons[i] = formatRings(rings)
+	}
+	return "MULTIPOLYGON(" + strings.Join(polygons, ",") + ")"
+}
+
+func (m MultiPolygon[S]) space() S {
+	var space S
+	return space
+}
+
+func (m MultiPolygon[S]) ODataLiteral() string {
+	return literal[S](m.SRID, m.wellKnownText())
+}
+
+func (m MultiPolygon[S]) MarshalJSON() ([]byte, error) {
+	return marshalGeoJSON[S]("MultiPolygon", m.Polygons, m.SRID)
+}
+
+func (m *MultiPolygon[S]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return unmarshalGeoJSON(data, "MultiPolygon", &m.Polygons, &m.SRID)
+}
+
+// Collection holds shapes of any kind, it is a GeometryCollection in GeoJSON
+type Collection[S Space] struct {
+	Shapes []Shape[S]
+	SRID   int
+}
+
+func (c Collection[S]) wellKnownText() string {
+	shapes := make([]string, len(c.Shapes))
+	for i, shape := range c.Shapes {
+		shapes[i] = shape.wellKnownText()
+	}
+	return "COLLECTION(" + strings.Join(shapes, ",") + ")"
+}
+
+func (c Collection[S]) space() S {
+	var space S
+	return space
+}
+
+func (c Collection[S]) ODataLiteral() string {
+	return literal[S](c.SRID, c.wellKnownText())
+}
+
+func (c Collection[S]) MarshalJSON() ([]byte, error) {
+	geometries := make([]json.RawMessage, len(c.Shapes))
+	for i, shape := range c.Shapes {
+		data, err := shape.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		geometries[i] = data
+	}
+	return json.Marshal(geoJSON{Type: "GeometryCollection", Geometries: geometries, Crs: crs[S](c.SRID)})
+}
+
+func (c *Collection[S]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	value, srid, err := parseGeoJSON(data, "GeometryCollection")
+	if err != nil {
+		return err
+	}
+	c.SRID = srid
+	c.Shapes = make([]Shape[S], len(value.Geometries))
+	for i, geometry := range value.Geometries {
+		if c.Shapes[i], err = decodeShape[S](geometry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Any holds a shape of any kind, for the abstract Edm.Geography and Edm.Geometry types
+type Any[S Space] struct {
+	Shape Shape[S]
+}
+
+func (a Any[S]) ODataLiteral() string {
+	if a.Shape == nil {
+		return "null"
+	}
+	return a.Shape.ODataLiteral()
+}
+
+func (a Any[S]) MarshalJSON() ([]byte, error) {
+	if a.Shape == nil {
+		return []byte("null"), nil
+	}
+	return a.Shape.MarshalJSON()
+}
+
+func (a *Any[S]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var err error
+	a.Shape, err = decodeShape[S](data)
+	return err
+}
@@ -5,10 +5,10 @@ go 1.18
 require (
 	github.com/Uffe-Code/go-nullable v0.1.2
 	github.com/stretchr/testify v1.7.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -2,81 +2,250 @@ package modelGenerator
 
 import (
 	"fmt"
-	"strconv"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
 )
 
 func generateModelStruct(entityType edmxEntityType) string {
-	structString := fmt.Sprintf("type %s struct {", entityType.Name)
+	structString := fmt.Sprintf("type %s struct {", entityType.goName())
+	embeddedName := entityType.embeddedFieldName()
+	if _, ok := entityType.getBaseType(); ok {
+		structString += "\n\t" + embeddedName
+	} else if embeddedName != "" {
+		structString += "\n\todataClient." + embeddedName
+	}
 
 	propertyKeys := sortedKeys(entityType.Properties)
+	navigationPropertyKeys := sortedKeys(entityType.NavigationProperties)
+	fieldNames := entityType.fieldNames(append(append([]string{}, propertyKeys...), navigationPropertyKeys...), embeddedName)
 
 	for _, propertyKey := range propertyKeys {
 		prop := entityType.Properties[propertyKey]
-		structString += fmt.Sprintf("\n\t%s %s", prop.Name, prop.goType())
+		tag := fmt.Sprintf("json:\"%s\"", prop.Name)
+		if trimCollection(prop.Type) == "Edm.Decimal" && prop.facets() != "" {
+			tag += fmt.Sprintf(" odata:\"%s\"", prop.facets())
+		}
+		structString += fmt.Sprintf("\n\t%s %s `%s`", fieldNames[prop.Name], prop.goType(), tag)
+	}
+
+	for _, navigationPropertyKey := range navigationPropertyKeys {
+		navigationProperty := entityType.NavigationProperties[navigationPropertyKey]
+		structString += fmt.Sprintf("\n\t%s %s `json:\"%s,omitempty\" odata:\"navigation\"`", fieldNames[navigationProperty.Name], navigationProperty.goType(), navigationProperty.Name)
 	}
 
 	return structString + "\n}"
 }
 
+// generateModelUnmarshaler generates the UnmarshalJSON of types with polymorphic properties, which decodes their values
+// into the types given by their @odata.type. Derived types need their own, since they would use the one of their base type.
+func generateModelUnmarshaler(entityType edmxEntityType) string {
+	return fmt.Sprintf(`func (m *%s) UnmarshalJSON(data []byte) error {
+	return odataClient.UnmarshalModel(data, m)
+}`, entityType.goName())
+}
+
+// generatePolymorphicInterface generates the interface which is implemented by the type and the types derived from it,
+// so values can be decoded into the type given by their @odata.type
+func generatePolymorphicInterface(entityType edmxEntityType) string {
+	goName := entityType.goName()
+	return fmt.Sprintf(`// Any%s is implemented by %s and the types derived from it
+type Any%s interface {
+	As%s() %s
+}
+
+func (m %s) As%s() %s {
+	return m
+}`, goName, goName, goName, goName, goName, goName, goName, goName)
+}
+
+// generateTypeRegistry registers the Go types of all entity and complex types by their qualified name
+func generateTypeRegistry(dataService edmxDataServices) string {
+	registrations := ""
+	for _, namespace := range sortedKeys(dataService.Schemas) {
+		schema := dataService.Schemas[namespace]
+		for _, types := range []map[string]edmxEntityType{schema.ComplexTypes, schema.EntityTypes} {
+			for _, name := range sortedKeys(types) {
+				structuredType := types[name]
+				registrations += fmt.Sprintf("\n\todataClient.RegisterType[%s](\"%s\")", structuredType.goName(), structuredType.qualifiedName())
+				if structuredType.isPolymorphic() {
+					registrations += fmt.Sprintf("\n\todataClient.RegisterFallbackType[Any%s, %s]()", structuredType.goName(), structuredType.goName())
+				}
+			}
+		}
+	}
+	if registrations == "" {
+		return ""
+	}
+	return "func init() {" + registrations + "\n}"
+}
+
 func generateModelDefinition(set edmxEntitySet) string {
 	entityType := set.getEntityType()
 
+	return generateCollectionFunction(entityType.goName(), set.Name)
+}
+
+// generatePolymorphicModelDefinition generates the collection of an entity set with derived types, where each
+// entity is decoded into the type given by its @odata.type
+func generatePolymorphicModelDefinition(set edmxEntitySet) string {
+	entityType := set.getEntityType()
+
+	return generateCollectionFunction("Any"+entityType.goName(), set.Name)
+}
+
+// generateDerivedModelDefinition generates the collection of a type derived from the entity type of the set,
+// which is addressed with a type-cast segment, e.g. People/Trippin.Employee
+func generateDerivedModelDefinition(set edmxEntitySet, derivedType edmxEntityType) string {
+	return generateCollectionFunction(derivedType.goName(), set.Name+"/"+derivedType.qualifiedName())
+}
+
+func generateCollectionFunction(typeName string, url string) string {
 	return fmt.Sprintf(`//goland:noinspection GoUnusedExportedFunction
 func New%sCollection(wrapper odataClient.Wrapper) odataClient.ODataModelCollection[%s] {
 	return modelDefinition[%s]{client: wrapper.ODataClient(), name: "%s", url: "%s"}
-}`, entityType.Name, entityType.Name, entityType.Name, entityType.Name, set.Name)
+}`, typeName, typeName, typeName, typeName, url)
 }
 
-func generateEnumStruct(enum edmxEnumType) string {
-	stringValues := map[string]string{}
-	intValues := map[int64]string{}
-	isIntValues := true
-
-	for _, member := range enum.Members {
-		stringValues[member.Name] = member.Value
-		i, err := strconv.ParseInt(member.Value, 10, 64)
-		if err != nil {
-			isIntValues = false
-		} else {
-			intValues[i] = member.Name
-		}
+func generateSingletonDefinition(singleton edmxEntitySet) string {
+	entityType := singleton.getEntityType()
+
+	return fmt.Sprintf(`//goland:noinspection GoUnusedExportedFunction
+func New%sSingleton(wrapper odataClient.Wrapper) odataClient.SingletonSet[%s] {
+	return odataClient.NewSingletonSet[%s](wrapper.ODataClient(), "%s")
+}`, goIdentifier(singleton.Name), entityType.goName(), entityType.goName(), singleton.Name)
+}
+
+func generateKeyFunction(entityType edmxEntityType) string {
+	if len(entityType.Key) == 0 {
+		return ""
 	}
 
-	goType := "string"
-	if isIntValues {
-		goType = "int64"
+	parameters := make([]string, len(entityType.Key))
+	keyProperties := make([]string, len(entityType.Key))
+	for i, propertyName := range entityType.Key {
+		prop := entityType.Properties[propertyName]
+		parameterName := goParameterName(goIdentifier(propertyName))
+		parameters[i] = fmt.Sprintf("%s %s", parameterName, prop.goType())
+		keyProperties[i] = fmt.Sprintf("\n\t\todataClient.KeyProperty{Name: \"%s\", Value: %s},", propertyName, parameterName)
 	}
-	goString := fmt.Sprintf(`type %s %s
 
-const (`, enum.Name, goType)
+	body := fmt.Sprintf("odataClient.Key(%s)", goParameterName(goIdentifier(entityType.Key[0])))
+	if len(entityType.Key) > 1 {
+		body = "odataClient.CompositeKey(" + strings.Join(keyProperties, "") + "\n\t)"
+	}
 
-	if isIntValues {
-		intKeys := sortedKeys(intValues)
-		for _, i := range intKeys {
-			key := intValues[i]
-			goString += fmt.Sprintf("\n\t%s %s = %d", key, enum.Name, i)
+	return fmt.Sprintf(`//goland:noinspection GoUnusedExportedFunction
+func %sKey(%s) odataClient.EntityKey {
+	return %s
+}`, entityType.goName(), strings.Join(parameters, ", "), body)
+}
+
+// goParameterName converts a property name to a parameter name, e.g. UserName to userName and ID to id
+func goParameterName(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes); i++ {
+		if i > 0 && i+1 < len(runes) && strings.ToLower(string(runes[i+1])) == string(runes[i+1]) {
+			break
 		}
-	} else {
-		stringKeys := sortedKeys(stringValues)
-		for _, key := range stringKeys {
-			str := stringValues[key]
-			goString += fmt.Sprintf("\n\t%s %s = \"%s\"", key, enum.Name, str)
+		lower := []rune(strings.ToLower(string(runes[i])))[0]
+		if lower == runes[i] {
+			break
 		}
+		runes[i] = lower
+	}
+	parameterName := string(runes)
+	if token.IsKeyword(parameterName) {
+		parameterName += "Value"
+	}
+	return parameterName
+}
+
+func generateEnumStruct(enum edmxEnumType) string {
+	memberNames := make([]string, len(enum.Members))
+	for i, member := range enum.Members {
+		memberNames[i] = member.Name
+	}
+	// members are prefixed with the enum name, since members of different enums can have the same name,
+	// and the Values function of the enum is reserved
+	enumName := enum.goName()
+	goMemberNames := uniqueNames(memberNames, "Values")
+	for name, goName := range goMemberNames {
+		goMemberNames[name] = enumName + goName
+	}
+	values := enum.memberValues()
+	sort.SliceStable(memberNames, func(i, j int) bool {
+		return values[memberNames[i]] < values[memberNames[j]]
+	})
+
+	enumVariable := goParameterName(enumName) + "Enum"
+	goString := fmt.Sprintf("type %s %s\n\nconst (", enumName, enum.goUnderlyingType())
+	for _, name := range memberNames {
+		goString += fmt.Sprintf("\n\t%s %s = %d", goMemberNames[name], enumName, values[name])
 	}
+	goString += fmt.Sprintf("\n)\n\nvar %s = odataClient.NewEnumType[%s](\"%s\", %t,", enumVariable, enumName, enum.qualifiedName(), enum.isFlags())
+	for _, name := range memberNames {
+		goString += fmt.Sprintf("\n\todataClient.EnumMember[%s]{Value: %s, Name: \"%s\"},", enumName, goMemberNames[name], name)
+	}
+	goString += "\n)"
+
+	goString += fmt.Sprintf(`
 
-	return goString + "\n)"
+// %[1]sValues returns all members of %[1]s in the order of their values
+//goland:noinspection GoUnusedExportedFunction
+func %[1]sValues() []%[1]s {
+	return %[2]s.Values()
 }
 
-func generateCodeFromSchema(packageName string, dataService edmxDataServices) string {
-	goCode := fmt.Sprintf(`package %s
+// Parse%[1]s returns the value of the member with the name in the service
+//goland:noinspection GoUnusedExportedFunction
+func Parse%[1]s(name string) (%[1]s, error) {
+	return %[2]s.Parse(name)
+}
 
-import (
-	"github.com/Uffe-Code/go-nullable/nullable"
-	"github.com/Uffe-Code/go-odata/odataClient"
-	"github.com/Uffe-Code/go-odata/date"
-	"time"
-)
+func (e %[1]s) String() string {
+	return %[2]s.Format(e)
+}
+
+func (e %[1]s) ODataLiteral() string {
+	return %[2]s.Literal(e)
+}
+
+func (e %[1]s) MarshalJSON() ([]byte, error) {
+	return %[2]s.EncodeJSON(e)
+}
+
+func (e *%[1]s) UnmarshalJSON(data []byte) error {
+	return %[2]s.DecodeJSON(data, e)
+}`, enumName, enumVariable)
+
+	if enum.isFlags() {
+		goString += fmt.Sprintf(`
+
+// Has returns true if all the flags are set
+func (e %[1]s) Has(flags %[1]s) bool {
+	return e&flags == flags
+}
 
+// With returns the value with the flags set
+func (e %[1]s) With(flags %[1]s) %[1]s {
+	return e | flags
+}
+
+// Without returns the value with the flags cleared
+func (e %[1]s) Without(flags %[1]s) %[1]s {
+	return e &^ flags
+}`, enumName)
+	}
+	return goString
+}
+
+func generateCodeFromSchema(packageName string, dataService edmxDataServices) (string, error) {
+	goCode := `
 type modelDefinition[T any] struct { client odataClient.ODataClient; name string; url string }
 
 func (md modelDefinition[T]) Name() string {
@@ -90,23 +259,219 @@ func (md modelDefinition[T]) Url() string {
 func (md modelDefinition[T]) DataSet() odataClient.ODataDataSet[T, odataClient.ODataModelDefinition[T]] {
 	return odataClient.NewDataSet[T](md.client, md)
 }
+`
+	generatedCollections := map[string]bool{}
+	for _, namespace := range sortedKeys(dataService.Schemas) {
+		for _, set := range dataService.Schemas[namespace].EntitySets {
+			generatedCollections[set.getEntityType().goName()] = true
+		}
+	}
 
-`, packageName)
-
-	for _, schema := range dataService.Schemas {
-		for _, enum := range schema.EnumTypes {
-			goCode += "\n" + generateEnumStruct(enum) + "\n"
+	for _, namespace := range sortedKeys(dataService.Schemas) {
+		schema := dataService.Schemas[namespace]
+		for _, name := range sortedKeys(schema.EnumTypes) {
+			goCode += "\n" + generateEnumStruct(schema.EnumTypes[name]) + "\n"
 		}
 
-		for _, complexType := range schema.ComplexTypes {
+		for _, name := range sortedKeys(schema.ComplexTypes) {
+			complexType := schema.ComplexTypes[name]
 			goCode += "\n" + generateModelStruct(complexType) + "\n"
+			if complexType.hasPolymorphicProperties() {
+				goCode += "\n" + generateModelUnmarshaler(complexType) + "\n"
+			}
+			if complexType.isPolymorphic() {
+				goCode += "\n" + generatePolymorphicInterface(complexType) + "\n"
+			}
+		}
+
+		for _, name := range sortedKeys(schema.EntityTypes) {
+			entityType := schema.EntityTypes[name]
+			goCode += "\n" + generateModelStruct(entityType) + "\n"
+			if entityType.hasPolymorphicProperties() {
+				goCode += "\n" + generateModelUnmarshaler(entityType) + "\n"
+			}
+			if entityType.isPolymorphic() {
+				goCode += "\n" + generatePolymorphicInterface(entityType) + "\n"
+			}
+			if keyFunction := generateKeyFunction(entityType); keyFunction != "" {
+				goCode += "\n" + keyFunction + "\n"
+			}
 		}
 
-		for _, set := range schema.EntitySets {
-			goCode += "\n" + generateModelStruct(set.getEntityType()) + "\n"
+		for _, name := range sortedKeys(schema.EntitySets) {
+			set := schema.EntitySets[name]
 			goCode += "\n" + generateModelDefinition(set) + "\n"
+			if set.getEntityType().isPolymorphic() && !generatedCollections["Any"+set.getEntityType().goName()] {
+				generatedCollections["Any"+set.getEntityType().goName()] = true
+				goCode += "\n" + generatePolymorphicModelDefinition(set) + "\n"
+			}
+			for _, derivedType := range dataService.derivedTypes(set.getEntityType()) {
+				if !generatedCollections[derivedType.goName()] {
+					generatedCollections[derivedType.goName()] = true
+					goCode += "\n" + generateDerivedModelDefinition(set, derivedType) + "\n"
+				}
+			}
+		}
+
+		for _, name := range sortedKeys(schema.Singletons) {
+			goCode += "\n" + generateSingletonDefinition(schema.Singletons[name]) + "\n"
+		}
+
+		generatedOperations := map[string]bool{}
+		for _, operation := range schema.Operations {
+			if operationFunction := generateBoundOperation(operation); operationFunction != "" && !generatedOperations[operationFunction] {
+				generatedOperations[operationFunction] = true
+				goCode += "\n" + operationFunction + "\n"
+			}
 		}
+
+		for _, operationImport := range schema.OperationImports {
+			if operationFunction := generateOperationImport(operationImport); operationFunction != "" {
+				goCode += "\n" + operationFunction + "\n"
+			}
+		}
+	}
+
+	if typeRegistry := generateTypeRegistry(dataService); typeRegistry != "" {
+		goCode += "\n" + typeRegistry + "\n"
+	}
+
+	return formatGoFile(packageName, goCode, dataService.config.imports)
+}
+
+// generatedImports are the packages which generated code can refer to, by their package name
+var generatedImports = map[string]string{
+	"context":     "context",
+	"date":        "github.com/Uffe-Code/go-odata/date",
+	"decimal":     "github.com/Uffe-Code/go-odata/decimal",
+	"geo":         "github.com/Uffe-Code/go-odata/geo",
+	"nullable":    "github.com/Uffe-Code/go-nullable/nullable",
+	"odataClient": "github.com/Uffe-Code/go-odata/odataClient",
+	"time":        "time",
+	"uuid":        "github.com/Uffe-Code/go-odata/uuid",
+}
+
+// formatGoFile adds the package clause and the imports of the packages used by the code, and formats it with gofmt.
+// The configured imports map package names to import paths in addition to the generated imports.
+func formatGoFile(packageName string, goCode string, configuredImports map[string]string) (string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package "+packageName+"\n"+goCode, 0)
+	if err != nil {
+		return "", fmt.Errorf("generated code is invalid: %w", err)
 	}
 
-	return goCode
+	usedImports := map[string]string{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				if importPath, ok := generatedImports[ident.Name]; ok {
+					usedImports[importPath] = ident.Name
+				} else if importPath, ok := configuredImports[ident.Name]; ok {
+					usedImports[importPath] = ident.Name
+				}
+			}
+		}
+		return true
+	})
+
+	imports := ""
+	for _, importPath := range sortedKeys(usedImports) {
+		if name := usedImports[importPath]; name != path.Base(importPath) {
+			imports += fmt.Sprintf("\n\t%s \"%s\"", name, importPath)
+		} else {
+			imports += fmt.Sprintf("\n\t\"%s\"", importPath)
+		}
+	}
+	if imports != "" {
+		imports = "\nimport (" + imports + "\n)\n"
+	}
+
+	formatted, err := format.Source([]byte(fmt.Sprintf("// Code generated by go-odata. DO NOT EDIT.\n\npackage %s\n%s%s", packageName, imports, goCode)))
+	if err != nil {
+		return "", fmt.Errorf("generated code is invalid: %w", err)
+	}
+	return string(formatted), nil
+}
+
+// operationParameters returns the Go parameters and the parameter map passed to odataClient for the
+// parameters of an operation. Parameter names which collide with the fixed parameters are suffixed.
+func operationParameters(operation edmxOperation, fixedParameters ...string) (string, string) {
+	parameters := ""
+	values := make([]string, 0, len(operation.callParameters()))
+	for _, parameter := range operation.callParameters() {
+		parameterName := goParameterName(goIdentifier(parameter.Name))
+		for _, fixedParameter := range fixedParameters {
+			if parameterName == fixedParameter {
+				parameterName += "Value"
+			}
+		}
+		parameters += fmt.Sprintf(", %s %s", parameterName, parameter.goType())
+		values = append(values, fmt.Sprintf("\"%s\": %s", parameter.Name, parameterName))
+	}
+	if len(values) == 0 {
+		return parameters, "nil"
+	}
+	return parameters, "map[string]interface{}{" + strings.Join(values, ", ") + "}"
+}
+
+func generateOperationCall(operation edmxOperation, functionName string, parameters string, target string, name string, values string) string {
+	call := "CallFunction"
+	if operation.IsAction {
+		call = "CallAction"
+	}
+
+	if operation.ReturnType == nil {
+		return fmt.Sprintf(`//goland:noinspection GoUnusedExportedFunction
+func %s(ctx context.Context%s) error {
+	_, err := odataClient.%s[struct{}](ctx, %s, "%s", %s)
+	return err
+}`, functionName, parameters, call, target, name, values)
+	}
+
+	returnType := operation.ReturnType.goType()
+	return fmt.Sprintf(`//goland:noinspection GoUnusedExportedFunction
+func %s(ctx context.Context%s) (%s, error) {
+	return odataClient.%s[%s](ctx, %s, "%s", %s)
+}`, functionName, parameters, returnType, call, returnType, target, name, values)
+}
+
+// generateBoundOperation generates a function calling the bound operation on an entity, or on a data set when
+// the operation is bound to a collection, e.g. PersonGetFavoriteAirline(ctx, entity)
+func generateBoundOperation(operation edmxOperation) string {
+	bindingParameter, ok := operation.bindingParameter()
+	if !ok {
+		return ""
+	}
+	bindingType := bindingParameter.Type
+	isCollection := strings.HasPrefix(bindingType, "Collection(")
+	if isCollection {
+		bindingType = bindingType[11 : len(bindingType)-1]
+	}
+	typeName := bindingParameter.schema.resolveTypeName(bindingType)
+	if typeName == "" {
+		return ""
+	}
+
+	functionName := typeName + goIdentifier(operation.Name) + operation.overloadSuffix
+	targetParameter := fmt.Sprintf(", entity odataClient.ODataEntity[%s]", typeName)
+	target := "entity"
+	if isCollection {
+		functionName = typeName + "Collection" + goIdentifier(operation.Name) + operation.overloadSuffix
+		targetParameter = fmt.Sprintf(", dataSet odataClient.ODataDataSet[%s, odataClient.ODataModelDefinition[%s]]", typeName, typeName)
+		target = "dataSet"
+	}
+
+	parameters, values := operationParameters(operation, "ctx", target)
+	return generateOperationCall(operation, functionName, targetParameter+parameters, target, operation.qualifiedName(), values)
+}
+
+// generateOperationImport generates a function per overload calling the unbound operation by the name of its import
+func generateOperationImport(operationImport edmxOperationImport) string {
+	var functions []string
+	for _, operation := range operationImport.getOperations() {
+		parameters, values := operationParameters(operation, "ctx", "wrapper")
+		target := "odataClient.ServiceRoot(wrapper.ODataClient())"
+		functionName := goIdentifier(operationImport.Name) + operation.overloadSuffix
+		functions = append(functions, generateOperationCall(operation, functionName, ", wrapper odataClient.Wrapper"+parameters, target, operationImport.Name, values))
+	}
+	return strings.Join(functions, "\n\n")
 }
@@ -0,0 +1,334 @@
+package modelGenerator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NullableStrategy is how properties and parameters which can be null are generated
+type NullableStrategy string
+
+const (
+	// NullableWrapper generates nullable.Nullable[T], which is the default
+	NullableWrapper NullableStrategy = "nullable"
+	// NullablePointer generates *T, which is nil for null
+	NullablePointer NullableStrategy = "pointer"
+	// NullableNone generates T, null is read as the zero value
+	NullableNone NullableStrategy = "none"
+)
+
+// Config customizes the generated code. It can be written as YAML or JSON and loaded with LoadConfig, e.g.
+//
+//	package: crm
+//	include: [accounts, contacts]
+//	exclude: ["*_audit"]
+//	nullable: pointer
+//	typeMapping:
+//	  Edm.Decimal: github.com/shopspring/decimal.Decimal
+//	types:
+//	  Microsoft.Dynamics.CRM.account:
+//	    name: CrmAccount
+//	    properties:
+//	      accountid: {name: ID}
+//	      revenue: {type: float64}
+type Config struct {
+	// Package is the package name of the generated code, which is used when the Generator has no PackageName
+	Package string `yaml:"package"`
+	// Include and Exclude are patterns which are added to the Include and Exclude of the Generator
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// Nullable is the strategy for properties and parameters which can be null, defaults to NullableWrapper
+	Nullable NullableStrategy `yaml:"nullable"`
+	// TypeMapping replaces the Go types of Edm primitive types, e.g. Edm.Decimal by float64. Types of other packages
+	// are given with their import path, e.g. github.com/shopspring/decimal.Decimal.
+	TypeMapping map[string]string `yaml:"typeMapping"`
+	// Types configures entity, complex and enum types by their name or namespace qualified name
+	Types map[string]TypeConfig `yaml:"types"`
+}
+
+// TypeConfig renames a type and configures its properties
+type TypeConfig struct {
+	// Name of the generated Go type
+	Name string `yaml:"name"`
+	// Properties configures the properties and navigation properties by their name in the service
+	Properties map[string]PropertyConfig `yaml:"properties"`
+}
+
+// PropertyConfig renames a property or replaces its Go type
+type PropertyConfig struct {
+	// Name of the struct field, the json tag keeps the name of the property
+	Name string `yaml:"name"`
+	// Type of the struct field instead of the type generated for the property, including its nullability,
+	// e.g. *string or github.com/shopspring/decimal.Decimal
+	Type string `yaml:"type"`
+}
+
+// LoadConfig reads the configuration from a YAML or JSON file. Unknown keys are reported as errors.
+func LoadConfig(fileName string) (Config, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return Config{}, err
+	}
+	config, err := parseConfig(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return config, nil
+}
+
+func parseConfig(data []byte) (Config, error) {
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// codeConfig holds the parts of the Config which change the generated code, resolved to the types of the data service.
+// It is shared by all copies of the data service.
+type codeConfig struct {
+	nullable NullableStrategy
+	// typeMapping maps Edm primitive types to Go types
+	typeMapping map[string]string
+	// fieldNames and fieldTypes are keyed by the qualified type name and the property name, e.g. Trippin.Person/Age
+	fieldNames map[string]string
+	fieldTypes map[string]string
+	// imports maps the package names of the configured Go types to their import paths
+	imports map[string]string
+}
+
+func newCodeConfig() *codeConfig {
+	return &codeConfig{
+		typeMapping: map[string]string{},
+		fieldNames:  map[string]string{},
+		fieldTypes:  map[string]string{},
+		imports:     map[string]string{},
+	}
+}
+
+func (c *codeConfig) fieldName(qualifiedName string, propertyName string) string {
+	if c == nil {
+		return ""
+	}
+	return c.fieldNames[qualifiedName+"/"+propertyName]
+}
+
+func (c *codeConfig) fieldType(qualifiedName string, propertyName string) string {
+	if c == nil {
+		return ""
+	}
+	return c.fieldTypes[qualifiedName+"/"+propertyName]
+}
+
+func (c *codeConfig) mappedType(edmType string) string {
+	if c == nil {
+		return ""
+	}
+	return c.typeMapping[edmType]
+}
+
+// nullableType wraps the Go type of a property or parameter which can be null
+func (c *codeConfig) nullableType(goType string) string {
+	if c == nil {
+		return "nullable.Nullable[" + goType + "]"
+	}
+	switch c.nullable {
+	case NullablePointer:
+		return "*" + goType
+	case NullableNone:
+		return goType
+	}
+	return "nullable.Nullable[" + goType + "]"
+}
+
+// versionSuffix matches the major version at the end of import paths, e.g. /v2 or .v3 of gopkg.in/yaml.v3
+var versionSuffix = regexp.MustCompile(`[/.]v[0-9]+$`)
+
+// addGoType returns the configured Go type as it is written in the generated code, and adds the import of its package.
+// Types of other packages are given with their import path, e.g. []github.com/shopspring/decimal.Decimal
+// is written as []decimal.Decimal. Packages with the name of another imported package get an alias,
+// e.g. shopspringDecimal.
+func (c *codeConfig) addGoType(goType string) (string, error) {
+	typeName := strings.TrimLeft(goType, "*[]")
+	modifiers := goType[:len(goType)-len(typeName)]
+	if dot := packageSeparator(typeName); dot >= 0 {
+		importPath := typeName[:dot]
+		if generatedPath, ok := generatedImports[importPath]; ok {
+			importPath = generatedPath
+		}
+		goType = modifiers + c.addImport(importPath) + typeName[dot:]
+	}
+	if _, err := parser.ParseExpr(goType); err != nil || typeName == "" {
+		return "", fmt.Errorf("invalid Go type %q", modifiers+typeName)
+	}
+	return goType, nil
+}
+
+// addImport returns the name the package is referred to by in the generated code
+func (c *codeConfig) addImport(importPath string) string {
+	isTaken := func(name string) bool {
+		if existingPath, ok := generatedImports[name]; ok && existingPath != importPath {
+			return true
+		}
+		existingPath, ok := c.imports[name]
+		return ok && existingPath != importPath
+	}
+	trimmedPath := versionSuffix.ReplaceAllString(importPath, "")
+	name := path.Base(trimmedPath)
+	if isTaken(name) {
+		name = goParameterName(goIdentifier(path.Base(path.Dir(trimmedPath)) + "_" + name))
+	}
+	for i, alias := 2, name; isTaken(name); i++ {
+		name = alias + strconv.Itoa(i)
+	}
+	c.imports[name] = importPath
+	return name
+}
+
+// packageSeparator returns the index of the dot between the package and the exported name of the type,
+// or -1 for types without a package. Type arguments in brackets are not taken into account.
+func packageSeparator(typeName string) int {
+	if bracket := strings.IndexByte(typeName, '['); bracket >= 0 {
+		typeName = typeName[:bracket]
+	}
+	for i := strings.LastIndex(typeName, "/") + 1; i < len(typeName)-1; i++ {
+		if typeName[i] == '.' && unicode.IsUpper(rune(typeName[i+1])) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isExportedIdentifier(name string) bool {
+	return token.IsIdentifier(name) && ast.IsExported(name)
+}
+
+// apply resolves the configuration against the types of the data service, which is changed in place
+func (c Config) apply(dataService edmxDataServices) error {
+	config := dataService.config
+	switch c.Nullable {
+	case "", NullableWrapper, NullablePointer, NullableNone:
+		config.nullable = c.Nullable
+	default:
+		return fmt.Errorf("unknown nullable strategy %q, use nullable, pointer or none", c.Nullable)
+	}
+
+	for _, edmType := range sortedKeys(c.TypeMapping) {
+		goType, err := config.addGoType(c.TypeMapping[edmType])
+		if err != nil {
+			return fmt.Errorf("type mapping of %s: %w", edmType, err)
+		}
+		config.typeMapping[edmType] = goType
+	}
+
+	typeConfigs := map[string]TypeConfig{}
+	for name, typeConfig := range c.Types {
+		typeConfigs[dataService.normalizeQualifiedName(name)] = typeConfig
+	}
+	matched := map[string]bool{}
+	configuredTypes := map[string]TypeConfig{}
+	qualifiedNames := dataService.qualifiedTypeNames()
+	for _, qualifiedName := range qualifiedNames {
+		configName := qualifiedName
+		typeConfig, ok := typeConfigs[configName]
+		if !ok {
+			_, configName = splitQualifiedName(qualifiedName)
+			if typeConfig, ok = typeConfigs[configName]; !ok {
+				continue
+			}
+		}
+		matched[configName] = true
+		configuredTypes[qualifiedName] = typeConfig
+		structuredType, isStructured := dataService.lookupStructuredType(qualifiedName)
+		if typeConfig.Name != "" {
+			if !isExportedIdentifier(typeConfig.Name) {
+				return fmt.Errorf("name %q of %s is not an exported Go identifier", typeConfig.Name, qualifiedName)
+			}
+			dataService.goNames[qualifiedName] = typeConfig.Name
+		}
+		for _, propertyName := range sortedKeys(typeConfig.Properties) {
+			propertyConfig := typeConfig.Properties[propertyName]
+			key := qualifiedName + "/" + propertyName
+			if !isStructured || !structuredType.hasProperty(propertyName) {
+				return fmt.Errorf("property %s of the configuration is not a property of %s", propertyName, qualifiedName)
+			}
+			if propertyConfig.Name != "" {
+				if !isExportedIdentifier(propertyConfig.Name) {
+					return fmt.Errorf("name %q of %s is not an exported Go identifier", propertyConfig.Name, key)
+				}
+				config.fieldNames[key] = propertyConfig.Name
+			}
+			if propertyConfig.Type != "" {
+				goType, err := config.addGoType(propertyConfig.Type)
+				if err != nil {
+					return fmt.Errorf("type of %s: %w", key, err)
+				}
+				config.fieldTypes[key] = goType
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(typeConfigs) {
+		if !matched[name] {
+			return fmt.Errorf("type %s of the configuration is not a type of the service, or it is excluded", name)
+		}
+	}
+
+	typesByGoName := map[string]string{}
+	for _, qualifiedName := range qualifiedNames {
+		goName := dataService.goName(qualifiedName)
+		if otherName, ok := typesByGoName[goName]; ok {
+			return fmt.Errorf("types %s and %s are both generated as %s, rename one of them", otherName, qualifiedName, goName)
+		}
+		typesByGoName[goName] = qualifiedName
+	}
+
+	for _, qualifiedName := range sortedKeys(configuredTypes) {
+		if structuredType, ok := dataService.lookupStructuredType(qualifiedName); ok {
+			if err := config.checkFieldNames(structuredType); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkFieldNames returns an error when a configured field name of the type is the name of another field of its struct
+func (c *codeConfig) checkFieldNames(structuredType edmxEntityType) error {
+	qualifiedName := structuredType.qualifiedName()
+	propertyNames := append(sortedKeys(structuredType.Properties), sortedKeys(structuredType.NavigationProperties)...)
+	fields := map[string]string{}
+	if embeddedName := structuredType.embeddedFieldName(); embeddedName != "" {
+		fields[embeddedName] = "the embedded " + embeddedName
+	}
+	for _, propertyName := range propertyNames {
+		if fieldName := goIdentifier(propertyName); c.fieldName(qualifiedName, propertyName) == "" && fields[fieldName] == "" {
+			fields[fieldName] = "property " + propertyName
+		}
+	}
+	for _, propertyName := range propertyNames {
+		fieldName := c.fieldName(qualifiedName, propertyName)
+		if fieldName == "" {
+			continue
+		}
+		if other, ok := fields[fieldName]; ok {
+			return fmt.Errorf("name %s of %s/%s is already the name of the field of %s, rename one of them", fieldName, qualifiedName, propertyName, other)
+		}
+		fields[fieldName] = "property " + propertyName
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package modelGenerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+const configEdmxSchema = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+<edmx:DataServices>
+<Schema Namespace="Microsoft.Dynamics.CRM" Alias="mscrm" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+<EntityType Name="account">
+<Key><PropertyRef Name="accountid"/></Key>
+<Property Name="accountid" Type="Edm.Guid" Nullable="false"/>
+<Property Name="name" Type="Edm.String"/>
+<Property Name="revenue" Type="Edm.Decimal"/>
+<Property Name="createdon" Type="Edm.DateTimeOffset"/>
+<NavigationProperty Name="primarycontactid" Type="mscrm.contact"/>
+</EntityType>
+<EntityType Name="contact">
+<Key><PropertyRef Name="contactid"/></Key>
+<Property Name="contactid" Type="Edm.Guid" Nullable="false"/>
+<Property Name="fullname" Type="Edm.String"/>
+</EntityType>
+<EntityType Name="account_audit">
+<Key><PropertyRef Name="auditid"/></Key>
+<Property Name="auditid" Type="Edm.Guid" Nullable="false"/>
+</EntityType>
+<EntityContainer Name="System">
+<EntitySet Name="accounts" EntityType="mscrm.account"/>
+<EntitySet Name="contacts" EntityType="mscrm.contact"/>
+<EntitySet Name="account_audits" EntityType="mscrm.account_audit"/>
+</EntityContainer>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`
+
+func Test_parseConfig(t *testing.T) {
+	expected := Config{
+		Package:     "crm",
+		Exclude:     []string{"*_audit*"},
+		Nullable:    NullablePointer,
+		TypeMapping: map[string]string{"Edm.Decimal": "float64"},
+		Types: map[string]TypeConfig{
+			"account": {Name: "Account", Properties: map[string]PropertyConfig{"accountid": {Name: "ID"}}},
+		},
+	}
+
+	fromYaml, err := parseConfig([]byte(`
+package: crm
+exclude: ["*_audit*"]
+nullable: pointer
+typeMapping:
+  Edm.Decimal: float64
+types:
+  account:
+    name: Account
+    properties:
+      accountid: {name: ID}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, fromYaml)
+
+	fromJson, err := parseConfig([]byte(`{
+  "package": "crm",
+  "exclude": ["*_audit*"],
+  "nullable": "pointer",
+  "typeMapping": {"Edm.Decimal": "float64"},
+  "types": {"account": {"name": "Account", "properties": {"accountid": {"name": "ID"}}}}
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, fromJson)
+
+	empty, err := parseConfig(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Config{}, empty)
+
+	_, err = parseConfig([]byte("nullabel: pointer"))
+	assert.Error(t, err)
+}
+
+func Test_Generate_with_config(t *testing.T) {
+	config := Config{
+		Package:  "crm",
+		Exclude:  []string{"*_audit*"},
+		Nullable: NullablePointer,
+		TypeMapping: map[string]string{
+			"Edm.Decimal":        "github.com/shopspring/decimal.Decimal",
+			"Edm.DateTimeOffset": "string",
+		},
+		Types: map[string]TypeConfig{
+			"Microsoft.Dynamics.CRM.account": {
+				Name: "Account",
+				Properties: map[string]PropertyConfig{
+					"accountid":        {Name: "ID"},
+					"name":             {Type: "string"},
+					"primarycontactid": {Name: "PrimaryContact"},
+				},
+			},
+			"mscrm.contact": {Name: "Contact"},
+		},
+	}
+	code, err := Generator{Metadata: []byte(configEdmxSchema), Config: &config}.Generate()
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "package crm\n")
+	assert.Contains(t, code, `import (
+	"github.com/Uffe-Code/go-odata/odataClient"
+	"github.com/Uffe-Code/go-odata/uuid"
+	shopspringDecimal "github.com/shopspring/decimal"
+)`)
+	assert.Contains(t, code, `type Account struct {
+	odataClient.EntityMetadata
+	ID             uuid.UUID                  `+"`"+`json:"accountid"`+"`"+`
+	Createdon      *string                    `+"`"+`json:"createdon"`+"`"+`
+	Name           string                     `+"`"+`json:"name"`+"`"+`
+	Revenue        *shopspringDecimal.Decimal `+"`"+`json:"revenue"`+"`"+`
+	PrimaryContact *Contact                   `+"`"+`json:"primarycontactid,omitempty" odata:"navigation"`+"`"+`
+}`)
+	assert.Contains(t, code, "func AccountKey(accountid uuid.UUID) odataClient.EntityKey {")
+	assert.Contains(t, code, "func NewAccountCollection(wrapper odataClient.Wrapper) odataClient.ODataModelCollection[Account] {")
+	assert.Contains(t, code, "type Contact struct {")
+	assert.Contains(t, code, `odataClient.RegisterType[Contact]("Microsoft.Dynamics.CRM.contact")`)
+	assert.NotContains(t, code, "Audit")
+}
+
+func Test_Generate_with_config_file(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "odatagen.yaml")
+	assert.NoError(t, writeTestFile(configFile, "include: [contacts]\nnullable: none\n"))
+
+	code, err := Generator{Metadata: []byte(configEdmxSchema), PackageName: "crm", ConfigFile: configFile}.Generate()
+	assert.NoError(t, err)
+	assert.Regexp(t, "Fullname +string +`json:\"fullname\"`", code)
+	assert.NotContains(t, code, "type Account struct")
+
+	_, err = Generator{Metadata: []byte(configEdmxSchema), PackageName: "crm", ConfigFile: configFile + ".missing"}.Generate()
+	assert.Error(t, err)
+}
+
+func Test_Config_apply_errors(t *testing.T) {
+	for name, config := range map[string]Config{
+		"nullable strategy": {Nullable: "optional"},
+		"type name":         {Types: map[string]TypeConfig{"account": {Name: "crm account"}}},
+		"property name":     {Types: map[string]TypeConfig{"account": {Properties: map[string]PropertyConfig{"name": {Name: "name"}}}}},
+		"property type":     {Types: map[string]TypeConfig{"account": {Properties: map[string]PropertyConfig{"name": {Type: "map[string"}}}}},
+		"type mapping":      {TypeMapping: map[string]string{"Edm.Guid": "[]"}},
+		"clashing names":    {Types: map[string]TypeConfig{"account": {Name: "Contact"}}},
+		"unknown type":      {Types: map[string]TypeConfig{"acount": {Name: "Account"}}},
+		"unknown namespace": {Types: map[string]TypeConfig{"Microsoft.Dynamics.account": {Name: "Account"}}},
+		"unknown property":  {Types: map[string]TypeConfig{"account": {Properties: map[string]PropertyConfig{"fullname": {Name: "FullName"}}}}},
+		"clashing field":    {Types: map[string]TypeConfig{"account": {Properties: map[string]PropertyConfig{"accountid": {Name: "Name"}}}}},
+		"clashing fields": {Types: map[string]TypeConfig{"account": {Properties: map[string]PropertyConfig{
+			"accountid": {Name: "ID"},
+			"name":      {Name: "ID"},
+		}}}},
+		"embedded field": {Types: map[string]TypeConfig{"account": {Properties: map[string]PropertyConfig{"accountid": {Name: "EntityMetadata"}}}}},
+	} {
+		ds, _ := parseEdmx([]byte(configEdmxSchema))
+		assert.Error(t, config.apply(ds), name)
+	}
+}
+
+func Test_codeConfig_addGoType(t *testing.T) {
+	config := newCodeConfig()
+	for _, goType := range [][2]string{
+		{"float64", "float64"},
+		{"*string", "*string"},
+		{"map[string]interface{}", "map[string]interface{}"},
+		{"time.Duration", "time.Duration"},
+		{"[]encoding/json.RawMessage", "[]json.RawMessage"},
+		{"gopkg.in/yaml.v3.Node", "yaml.Node"},
+		{"github.com/jackc/pgx/v5/pgtype.Numeric", "pgtype.Numeric"},
+		{"nullable.Nullable[decimal.Decimal]", "nullable.Nullable[decimal.Decimal]"},
+		{"github.com/shopspring/decimal.Decimal", "shopspringDecimal.Decimal"},
+		{"*github.com/example/shopspring/decimal.D", "*shopspringDecimal2.D"},
+	} {
+		converted, err := config.addGoType(goType[0])
+		assert.NoError(t, err)
+		assert.Equal(t, goType[1], converted)
+	}
+	assert.Equal(t, map[string]string{
+		"time":               "time",
+		"json":               "encoding/json",
+		"yaml":               "gopkg.in/yaml.v3",
+		"pgtype":             "github.com/jackc/pgx/v5/pgtype",
+		"nullable":           "github.com/Uffe-Code/go-nullable/nullable",
+		"shopspringDecimal":  "github.com/shopspring/decimal",
+		"shopspringDecimal2": "github.com/example/shopspring/decimal",
+	}, config.imports)
+}
@@ -2,7 +2,7 @@ package modelGenerator
 
 import (
 	"encoding/xml"
-	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -19,6 +19,20 @@ func (es rawEdmxEntitySet) toEntitySet(schema edmxSchema) edmxEntitySet {
 	}
 }
 
+type rawEdmxSingleton struct {
+	Name string `xml:"Name,attr"`
+	Type string `xml:"Type,attr"`
+}
+
+// toEntitySet converts the singleton to an entity set, since both refer to an entity type of a schema
+func (s rawEdmxSingleton) toEntitySet(schema edmxSchema) edmxEntitySet {
+	return edmxEntitySet{
+		schema:     schema,
+		Name:       s.Name,
+		EntityType: s.Type,
+	}
+}
+
 type edmxEntitySet struct {
 	schema     edmxSchema
 	Name       string
@@ -26,19 +40,27 @@ type edmxEntitySet struct {
 }
 
 func (s edmxEntitySet) getEntityType() edmxEntityType {
-	namespace := s.EntityType[0:strings.LastIndex(s.EntityType, ".")]
-	entityTypeKey := s.EntityType[len(namespace)+1 : len(s.EntityType)]
-	return s.schema.dataService.Schemas[namespace].EntityTypes[entityTypeKey]
+	namespace, entityTypeKey := splitQualifiedName(s.EntityType)
+	schema, _ := s.schema.dataService.lookupSchema(namespace)
+	return schema.EntityTypes[entityTypeKey]
 }
 
 type edmxProperty struct {
-	Name     string `xml:"Name,attr"`
-	Type     string `xml:"Type,attr"`
-	Nullable string `xml:"Nullable,attr"`
-	schema   edmxSchema
+	Name      string `xml:"Name,attr"`
+	Type      string `xml:"Type,attr"`
+	Nullable  string `xml:"Nullable,attr"`
+	Precision string `xml:"Precision,attr"`
+	Scale     string `xml:"Scale,attr"`
+	schema    edmxSchema
+	// structuredType is the qualified name of the entity or complex type which declares the property
+	structuredType string
 }
 
 func (p edmxProperty) goType() string {
+	config := p.schema.dataService.config
+	if fieldType := config.fieldType(p.structuredType, p.Name); fieldType != "" {
+		return fieldType
+	}
 	propertyType := p.Type
 	isCollection := false
 	if strings.HasPrefix(p.Type, "Collection(") {
@@ -55,32 +77,50 @@ func (p edmxProperty) goType() string {
 		goType = "int32"
 	case "Edm.Int64":
 		goType = "int64"
-	case "Edm.Double", "Edm.Decimal", "Edm.Float":
+	case "Edm.Double", "Edm.Float":
 		goType = "float64"
+	case "Edm.Single":
+		goType = "float32"
+	case "Edm.Decimal":
+		goType = "decimal.Decimal"
 	case "Edm.Boolean":
 		goType = "bool"
 	case "Edm.DateTime", "Edm.DateTimeOffset":
 		goType = "time.Time"
 	case "Edm.Date":
 		goType = "date.Date"
+	case "Edm.TimeOfDay":
+		goType = "date.TimeOfDay"
+	case "Edm.Duration":
+		goType = "date.Duration"
+	case "Edm.Guid":
+		goType = "uuid.UUID"
+	case "Edm.Binary", "Edm.Stream":
+		goType = "[]byte"
 	case "Edm.Byte":
 		goType = "byte"
 	case "Edm.SByte":
 		goType = "int8"
 	default:
-		if strings.HasPrefix(propertyType, p.schema.Namespace) {
-			entityTypeKey := strings.Replace(propertyType, p.schema.Namespace+".", "", 1)
-			if enumType, ok := p.schema.EnumTypes[entityTypeKey]; ok {
-				goType = enumType.Name
-			}
-			if complexType, ok := p.schema.ComplexTypes[entityTypeKey]; ok {
-				goType = complexType.Name
-			}
+		if spatialType := geoType(propertyType); spatialType != "" {
+			goType = spatialType
+		} else if typeName := p.schema.resolveTypeName(propertyType); typeName != "" {
+			goType = typeName
+		}
+	}
+
+	if mappedType := config.mappedType(propertyType); mappedType != "" {
+		goType = mappedType
+	} else if p.isPolymorphic() {
+		// the interface is nil for null, so it is not wrapped
+		goType = "Any" + goType
+		if !isCollection {
+			return goType
 		}
 	}
 
 	if !isCollection && (p.Nullable == "" || strings.ToLower(p.Nullable) == "true") {
-		goType = "nullable.Nullable[" + goType + "]"
+		goType = config.nullableType(goType)
 	}
 
 	if isCollection {
@@ -89,78 +129,423 @@ func (p edmxProperty) goType() string {
 	return goType
 }
 
+// geoType returns the type of the geo package for Edm.Geography and Edm.Geometry types, e.g. geo.Point[geo.Geography]
+// for Edm.GeographyPoint, or an empty string for other types
+func geoType(edmType string) string {
+	for _, space := range []string{"Geography", "Geometry"} {
+		if !strings.HasPrefix(edmType, "Edm."+space) {
+			continue
+		}
+		switch kind := strings.TrimPrefix(edmType, "Edm."+space); kind {
+		case "":
+			return "geo.Any[geo." + space + "]"
+		case "Point", "LineString", "Polygon", "MultiPoint", "MultiLineString", "MultiPolygon", "Collection":
+			return "geo." + kind + "[geo." + space + "]"
+		}
+	}
+	return ""
+}
+
+// facets returns the precision and scale of decimal properties, which the Go type can't express, as the options
+// of the odata tag like precision=18,scale=2. The client checks them before sending a model.
+func (p edmxProperty) facets() string {
+	var facets []string
+	if p.Precision != "" {
+		facets = append(facets, "precision="+p.Precision)
+	}
+	if p.Scale != "" {
+		facets = append(facets, "scale="+p.Scale)
+	}
+	return strings.Join(facets, ",")
+}
+
+type edmxNavigationProperty struct {
+	Name     string `xml:"Name,attr"`
+	Type     string `xml:"Type,attr"`
+	Nullable string `xml:"Nullable,attr"`
+	schema   edmxSchema
+	// structuredType is the qualified name of the entity or complex type which declares the navigation property
+	structuredType string
+}
+
+func (p edmxNavigationProperty) isCollection() bool {
+	return strings.HasPrefix(p.Type, "Collection(")
+}
+
+// goType of a navigation property is a slice for collections and a pointer for single entities,
+// so it can be left out when the navigation property isn't expanded. Entity types with derived types
+// are generated as their Any interface, which is nil when it isn't expanded.
+func (p edmxNavigationProperty) goType() string {
+	if fieldType := p.schema.dataService.config.fieldType(p.structuredType, p.Name); fieldType != "" {
+		return fieldType
+	}
+	entityType := p.Type
+	if p.isCollection() {
+		entityType = p.Type[11 : len(p.Type)-1]
+	}
+	goType := p.schema.resolveTypeName(entityType)
+	if goType == "" {
+		goType = "interface{}"
+	} else if p.isPolymorphic() {
+		goType = "Any" + goType
+	}
+	if p.isCollection() {
+		return "[]" + goType
+	}
+	if p.isPolymorphic() {
+		return goType
+	}
+	return "*" + goType
+}
+
+// isPolymorphic returns true if the navigation property is generated as the Any interface of its entity type,
+// because the entities can be of a derived type
+func (p edmxNavigationProperty) isPolymorphic() bool {
+	if p.schema.dataService.config.fieldType(p.structuredType, p.Name) != "" {
+		return false
+	}
+	entityType, ok := p.schema.dataService.lookupStructuredType(trimCollection(p.Type))
+	return ok && entityType.isPolymorphic()
+}
+
 type edmxEntityType struct {
-	Name       string
-	Properties map[string]edmxProperty
+	Name                 string
+	BaseType             string
+	Abstract             bool
+	Properties           map[string]edmxProperty
+	NavigationProperties map[string]edmxNavigationProperty
+	Key                  []string
+	isComplexType        bool
+	schema               edmxSchema
+}
+
+func (e edmxEntityType) qualifiedName() string {
+	return e.schema.Namespace + "." + e.Name
+}
+
+// goName is the name of the generated Go type
+func (e edmxEntityType) goName() string {
+	return e.schema.dataService.goName(e.qualifiedName())
+}
+
+// getBaseType returns the entity or complex type which the type is derived from
+func (e edmxEntityType) getBaseType() (edmxEntityType, bool) {
+	if e.BaseType == "" {
+		return edmxEntityType{}, false
+	}
+	return e.schema.dataService.lookupStructuredType(e.BaseType)
+}
+
+// hasProperty returns true if the type declares the property or navigation property, inherited ones are not included
+func (e edmxEntityType) hasProperty(name string) bool {
+	_, isProperty := e.Properties[name]
+	_, isNavigationProperty := e.NavigationProperties[name]
+	return isProperty || isNavigationProperty
+}
+
+// embeddedFieldName returns the name of the struct embedded in the generated struct, which is the base type
+// or the EntityMetadata of entity types without a base type
+func (e edmxEntityType) embeddedFieldName() string {
+	if baseType, ok := e.getBaseType(); ok {
+		return baseType.goName()
+	}
+	if !e.isComplexType {
+		return "EntityMetadata"
+	}
+	return ""
+}
+
+// isDerivedFrom returns true if the type inherits from the base type through its chain of base types
+func (e edmxEntityType) isDerivedFrom(baseType edmxEntityType) bool {
+	visited := map[string]bool{e.qualifiedName(): true}
+	current, ok := e.getBaseType()
+	for ok && !visited[current.qualifiedName()] {
+		if current.qualifiedName() == baseType.qualifiedName() {
+			return true
+		}
+		visited[current.qualifiedName()] = true
+		current, ok = current.getBaseType()
+	}
+	return false
 }
 
 type rawEdmxEntityType struct {
-	Name       string         `xml:"Name,attr"`
-	Properties []edmxProperty `xml:"Property"`
+	Name                 string                   `xml:"Name,attr"`
+	BaseType             string                   `xml:"BaseType,attr"`
+	Abstract             string                   `xml:"Abstract,attr"`
+	Key                  []rawEdmxKey             `xml:"Key"`
+	Properties           []edmxProperty           `xml:"Property"`
+	NavigationProperties []edmxNavigationProperty `xml:"NavigationProperty"`
+}
+
+type rawEdmxKey struct {
+	PropertyRefs []rawEdmxPropertyRef `xml:"PropertyRef"`
+}
+
+type rawEdmxPropertyRef struct {
+	Name string `xml:"Name,attr"`
 }
 
 func (e rawEdmxEntityType) toEdmxEntityType(schema edmxSchema) edmxEntityType {
 	entityType := edmxEntityType{
-		Name:       e.Name,
-		Properties: map[string]edmxProperty{},
+		Name:                 e.Name,
+		BaseType:             e.BaseType,
+		Abstract:             strings.ToLower(e.Abstract) == "true",
+		schema:               schema,
+		Properties:           map[string]edmxProperty{},
+		NavigationProperties: map[string]edmxNavigationProperty{},
+	}
+	for _, key := range e.Key {
+		for _, propertyRef := range key.PropertyRefs {
+			entityType.Key = append(entityType.Key, propertyRef.Name)
+		}
 	}
 	for _, prop := range e.Properties {
 		prop.schema = schema
+		prop.structuredType = schema.Namespace + "." + e.Name
 		entityType.Properties[prop.Name] = prop
 	}
+	for _, navigationProperty := range e.NavigationProperties {
+		navigationProperty.schema = schema
+		navigationProperty.structuredType = schema.Namespace + "." + e.Name
+		entityType.NavigationProperties[navigationProperty.Name] = navigationProperty
+	}
 	return entityType
 }
 
 type edmxXmlData struct {
 	XMLName      xml.Name              `xml:"Edmx"`
 	Version      string                `xml:"Version,attr"`
+	References   []rawEdmxReference    `xml:"Reference"`
 	DataServices []rawEdmxDataServices `xml:"DataServices"`
 }
 
 type edmxSchema struct {
-	dataService  edmxDataServices
-	Namespace    string
-	EntityTypes  map[string]edmxEntityType
-	EntitySets   map[string]edmxEntitySet
-	EnumTypes    map[string]edmxEnumType
-	ComplexTypes map[string]edmxEntityType
+	dataService      edmxDataServices
+	Namespace        string
+	Alias            string
+	EntityTypes      map[string]edmxEntityType
+	EntitySets       map[string]edmxEntitySet
+	Singletons       map[string]edmxEntitySet
+	EnumTypes        map[string]edmxEnumType
+	ComplexTypes     map[string]edmxEntityType
+	Operations       []edmxOperation
+	OperationImports []edmxOperationImport
+}
+
+// resolveTypeName returns the Go type name of an enum, complex or entity type of any of the schemas,
+// or an empty string if the type is not defined
+func (schema edmxSchema) resolveTypeName(qualifiedName string) string {
+	return schema.dataService.resolveTypeName(qualifiedName)
+}
+
+// resolveTypeName returns the Go type name of an enum, complex or entity type of any of the schemas,
+// or an empty string if the type is not defined
+func (ds edmxDataServices) resolveTypeName(qualifiedName string) string {
+	namespace, typeKey := splitQualifiedName(qualifiedName)
+	schema, ok := ds.lookupSchema(namespace)
+	if !ok {
+		return ""
+	}
+	if enumType, ok := schema.EnumTypes[typeKey]; ok {
+		return enumType.goName()
+	}
+	if complexType, ok := schema.ComplexTypes[typeKey]; ok {
+		return complexType.goName()
+	}
+	if entityType, ok := schema.EntityTypes[typeKey]; ok {
+		return entityType.goName()
+	}
+	return ""
 }
 
 type rawEdmxDataServices struct {
 	Schemas []rawEdmxSchema `xml:"Schema"`
 }
 
-func (ds *rawEdmxDataServices) toDataService() edmxDataServices {
-	dataService := &edmxDataServices{Schemas: map[string]edmxSchema{}}
-	for _, s := range ds.Schemas {
+// toDataService converts the schemas, the aliases map the aliases of the main document to the namespaces
+func toDataService(schemas []rawEdmxSchema, aliases map[string]string) edmxDataServices {
+	dataService := &edmxDataServices{
+		Schemas: map[string]edmxSchema{},
+		aliases: map[string]string{},
+		goNames: map[string]string{},
+		config:  newCodeConfig(),
+	}
+	for alias, namespace := range aliases {
+		dataService.aliases[alias] = namespace
+	}
+	for _, s := range schemas {
+		if _, ok := dataService.aliases[s.Alias]; s.Alias != "" && !ok {
+			dataService.aliases[s.Alias] = s.Namespace
+		}
+	}
+	for _, s := range schemas {
 		sc := s.toSchema(*dataService)
 		dataService.Schemas[sc.Namespace] = sc
 	}
+
+	dataService.resolveNames()
 	return *dataService
 }
 
+// qualifiedTypeNames returns the namespace qualified names of the entity, complex and enum types of all schemas
+func (ds edmxDataServices) qualifiedTypeNames() []string {
+	var qualifiedNames []string
+	for _, namespace := range sortedKeys(ds.Schemas) {
+		schema := ds.Schemas[namespace]
+		for _, types := range []map[string]edmxEntityType{schema.EntityTypes, schema.ComplexTypes} {
+			for _, name := range sortedKeys(types) {
+				qualifiedNames = append(qualifiedNames, types[name].qualifiedName())
+			}
+		}
+		for _, name := range sortedKeys(schema.EnumTypes) {
+			qualifiedNames = append(qualifiedNames, schema.EnumTypes[name].qualifiedName())
+		}
+	}
+	return qualifiedNames
+}
+
 type edmxDataServices struct {
 	Schemas map[string]edmxSchema
+	// aliases maps the aliases of schemas and included namespaces to the namespaces
+	aliases map[string]string
+	// goNames maps the namespace qualified names of the types to the names of the generated Go types
+	goNames map[string]string
+	// config changes the generated code, it is set by the Config of the Generator
+	config *codeConfig
+}
+
+// lookupSchema returns the schema with the namespace or alias
+func (ds edmxDataServices) lookupSchema(namespaceOrAlias string) (edmxSchema, bool) {
+	if namespace, ok := ds.aliases[namespaceOrAlias]; ok {
+		namespaceOrAlias = namespace
+	}
+	schema, ok := ds.Schemas[namespaceOrAlias]
+	return schema, ok
+}
+
+// goName returns the name of the generated Go type for the namespace qualified type name
+func (ds edmxDataServices) goName(qualifiedName string) string {
+	if goName, ok := ds.goNames[ds.normalizeQualifiedName(qualifiedName)]; ok {
+		return goName
+	}
+	_, name := splitQualifiedName(qualifiedName)
+	return goIdentifier(name)
+}
+
+func splitQualifiedName(qualifiedName string) (string, string) {
+	separator := strings.LastIndex(qualifiedName, ".")
+	if separator < 0 {
+		return "", qualifiedName
+	}
+	return qualifiedName[:separator], qualifiedName[separator+1:]
+}
+
+// normalizeQualifiedName replaces an alias in the qualified name by the namespace, e.g. Core.Location by Trippin.Core.Location
+func (ds edmxDataServices) normalizeQualifiedName(qualifiedName string) string {
+	namespace, name := splitQualifiedName(qualifiedName)
+	if aliasedNamespace, ok := ds.aliases[namespace]; ok {
+		return aliasedNamespace + "." + name
+	}
+	return qualifiedName
+}
+
+// lookupStructuredType returns the entity or complex type with the namespace qualified name from any of the schemas
+func (ds edmxDataServices) lookupStructuredType(qualifiedName string) (edmxEntityType, bool) {
+	namespace, name := splitQualifiedName(qualifiedName)
+	schema, ok := ds.lookupSchema(namespace)
+	if !ok {
+		return edmxEntityType{}, false
+	}
+	if entityType, ok := schema.EntityTypes[name]; ok {
+		return entityType, true
+	}
+	complexType, ok := schema.ComplexTypes[name]
+	return complexType, ok
+}
+
+// derivedTypes returns the entity or complex types of all schemas which are derived from the type
+func (ds edmxDataServices) derivedTypes(baseType edmxEntityType) []edmxEntityType {
+	var derivedTypes []edmxEntityType
+	for _, namespace := range sortedKeys(ds.Schemas) {
+		schema := ds.Schemas[namespace]
+		types := schema.EntityTypes
+		if baseType.isComplexType {
+			types = schema.ComplexTypes
+		}
+		for _, name := range sortedKeys(types) {
+			if derivedType := types[name]; derivedType.isDerivedFrom(baseType) {
+				derivedTypes = append(derivedTypes, derivedType)
+			}
+		}
+	}
+	return derivedTypes
+}
+
+// isPolymorphic returns true if values of the type can be of a derived type
+func (e edmxEntityType) isPolymorphic() bool {
+	return e.Abstract || len(e.schema.dataService.derivedTypes(e)) > 0
+}
+
+// isPolymorphic returns true if the property is generated as the Any interface of its complex type,
+// because its values can be of a derived type
+func (p edmxProperty) isPolymorphic() bool {
+	if p.schema.dataService.config.fieldType(p.structuredType, p.Name) != "" {
+		return false
+	}
+	complexType, ok := p.schema.dataService.lookupStructuredType(trimCollection(p.Type))
+	return ok && complexType.isPolymorphic()
+}
+
+// hasPolymorphicProperties returns true if the type or one of its base types has polymorphic properties
+// or navigation properties
+func (e edmxEntityType) hasPolymorphicProperties() bool {
+	visited := map[string]bool{}
+	for current, ok := e, true; ok && !visited[current.qualifiedName()]; current, ok = current.getBaseType() {
+		visited[current.qualifiedName()] = true
+		for _, property := range current.Properties {
+			if property.isPolymorphic() {
+				return true
+			}
+		}
+		for _, navigationProperty := range current.NavigationProperties {
+			if navigationProperty.isPolymorphic() {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 type rawEdmxSchema struct {
 	XMLName      xml.Name            `xml:"Schema"`
 	Namespace    string              `xml:"Namespace,attr"`
+	Alias        string              `xml:"Alias,attr"`
 	EntityTypes  []rawEdmxEntityType `xml:"EntityType"`
 	Containers   []rawEdmxContainer  `xml:"EntityContainer"`
 	EnumTypes    []edmxEnumType      `xml:"EnumType"`
 	ComplexTypes []rawEdmxEntityType `xml:"ComplexType"`
+	Functions    []rawEdmxOperation  `xml:"Function"`
+	Actions      []rawEdmxOperation  `xml:"Action"`
 }
 
 func (s rawEdmxSchema) toSchema(services edmxDataServices) edmxSchema {
 	schema := &edmxSchema{
 		dataService:  services,
 		Namespace:    s.Namespace,
+		Alias:        s.Alias,
 		EntityTypes:  map[string]edmxEntityType{},
 		EntitySets:   map[string]edmxEntitySet{},
+		Singletons:   map[string]edmxEntitySet{},
 		EnumTypes:    map[string]edmxEnumType{},
 		ComplexTypes: map[string]edmxEntityType{},
 	}
+	for _, function := range s.Functions {
+		schema.Operations = append(schema.Operations, function.toOperation(*schema, false))
+	}
+	for _, action := range s.Actions {
+		schema.Operations = append(schema.Operations, action.toOperation(*schema, true))
+	}
 	for _, e := range s.EntityTypes {
 		schema.EntityTypes[e.Name] = e.toEdmxEntityType(*schema)
 	}
@@ -169,56 +554,92 @@ func (s rawEdmxSchema) toSchema(services edmxDataServices) edmxSchema {
 			entitySet := es.toEntitySet(*schema)
 			schema.EntitySets[entitySet.Name] = entitySet
 		}
+		for _, singleton := range c.Singletons {
+			entitySet := singleton.toEntitySet(*schema)
+			schema.Singletons[entitySet.Name] = entitySet
+		}
+		for _, functionImport := range c.FunctionImports {
+			schema.OperationImports = append(schema.OperationImports, functionImport.toOperationImport(*schema))
+		}
+		for _, actionImport := range c.ActionImports {
+			schema.OperationImports = append(schema.OperationImports, actionImport.toOperationImport(*schema))
+		}
 	}
 	for _, enum := range s.EnumTypes {
+		enum.schema = *schema
 		schema.EnumTypes[enum.Name] = enum
 	}
-	for _, complexType := range s.ComplexTypes {
-		schema.ComplexTypes[complexType.Name] = complexType.toEdmxEntityType(*schema)
+	for _, c := range s.ComplexTypes {
+		complexType := c.toEdmxEntityType(*schema)
+		complexType.isComplexType = true
+		schema.ComplexTypes[complexType.Name] = complexType
 	}
 	return *schema
 }
 
 type rawEdmxContainer struct {
-	EntitySets []rawEdmxEntitySet `xml:"EntitySet"`
+	EntitySets      []rawEdmxEntitySet       `xml:"EntitySet"`
+	Singletons      []rawEdmxSingleton       `xml:"Singleton"`
+	FunctionImports []rawEdmxOperationImport `xml:"FunctionImport"`
+	ActionImports   []rawEdmxOperationImport `xml:"ActionImport"`
 }
 
 type edmxEnumType struct {
-	XMLName xml.Name         `xml:"EnumType"`
-	Name    string           `xml:"Name,attr"`
-	Members []edmxEnumMember `xml:"Member"`
+	XMLName        xml.Name         `xml:"EnumType"`
+	Name           string           `xml:"Name,attr"`
+	UnderlyingType string           `xml:"UnderlyingType,attr"`
+	IsFlags        string           `xml:"IsFlags,attr"`
+	Members        []edmxEnumMember `xml:"Member"`
+	schema         edmxSchema
 }
 
-type edmxEnumMember struct {
-	XMLName xml.Name `xml:"Member"`
-	Name    string   `xml:"Name,attr"`
-	Value   string   `xml:"Value,attr"`
+func (e edmxEnumType) isFlags() bool {
+	return strings.ToLower(e.IsFlags) == "true"
 }
 
-type apiErrorMessage struct {
-	Message string `xml:"message"`
+// goUnderlyingType is the integer type of the generated enum type, Edm.Int32 is the default underlying type
+func (e edmxEnumType) goUnderlyingType() string {
+	switch e.UnderlyingType {
+	case "Edm.Byte":
+		return "uint8"
+	case "Edm.SByte":
+		return "int8"
+	case "Edm.Int16":
+		return "int16"
+	case "Edm.Int64":
+		return "int64"
+	}
+	return "int32"
 }
 
-func parseEdmx(xmlData []byte) (edmxDataServices, error) {
-	var edmxData edmxXmlData
-	err := xml.Unmarshal(xmlData, &edmxData)
-	if err != nil {
-		var apiErr apiErrorMessage
-		err2 := xml.Unmarshal(xmlData, &apiErr)
-		if err2 == nil {
-			return edmxDataServices{}, fmt.Errorf("error from API: %s", apiErr.Message)
+// memberValues returns the values of the members, members without a value are numbered in order from 0
+func (e edmxEnumType) memberValues() map[string]int64 {
+	values := map[string]int64{}
+	for i, member := range e.Members {
+		value, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil {
+			value = int64(i)
 		}
-		return edmxDataServices{}, err
+		values[member.Name] = value
 	}
+	return values
+}
 
-	if edmxData.Version != "4.0" {
-		return edmxDataServices{}, fmt.Errorf("only version 4.0 is supported, got %s", edmxData.Version)
-	}
+func (e edmxEnumType) qualifiedName() string {
+	return e.schema.Namespace + "." + e.Name
+}
 
-	if len(edmxData.DataServices) != 1 {
-		return edmxDataServices{}, fmt.Errorf("unexpected amount of <edmx:DataServices> in Edmx source, got %d and expected 1", len(edmxData.DataServices))
-	}
+// goName is the name of the generated Go type
+func (e edmxEnumType) goName() string {
+	return e.schema.dataService.goName(e.qualifiedName())
+}
 
-	dataServices := edmxData.DataServices[0]
-	return dataServices.toDataService(), nil
+type edmxEnumMember struct {
+	XMLName xml.Name `xml:"Member"`
+	Name    string   `xml:"Name,attr"`
+	Value   string   `xml:"Value,attr"`
+}
+
+type apiErrorMessage struct {
+	Message string `xml:"message"`
 }
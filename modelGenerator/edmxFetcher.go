@@ -1,26 +1,53 @@
 package modelGenerator
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"unicode/utf8"
 )
 
-func fetchEdmx(url string) (edmxDataServices, error) {
-	client := &http.Client{}
+func fetchMetadata(client *http.Client, url string, headers map[string]string) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
 	request, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return edmxDataServices{}, err
+		return nil, err
 	}
-	request.Header.Set("Accept", "application/atom+xml")
+	request.Header.Set("Accept", "application/xml")
 	request.Header.Set("DataServiceVersion", "4.0")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
 	response, err := client.Do(request)
 	if err != nil {
-		return edmxDataServices{}, err
+		return nil, err
 	}
 	defer func() { _ = response.Body.Close() }()
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return edmxDataServices{}, err
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("fetching %s returned status %d: %s", url, response.StatusCode, abbreviate(string(body), 200))
+	}
+	if contentType := response.Header.Get("Content-Type"); strings.Contains(contentType, "html") {
+		return nil, fmt.Errorf("fetching %s returned %s instead of an EDMX document: %s", url, contentType, abbreviate(string(body), 200))
+	}
+	return body, nil
+}
+
+// abbreviate cuts the text after at most maxLength bytes, without splitting a UTF-8 encoded character
+func abbreviate(text string, maxLength int) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= maxLength {
+		return text
+	}
+	for maxLength > 0 && !utf8.RuneStart(text[maxLength]) {
+		maxLength--
 	}
-	return parseEdmx(body)
+	return text[:maxLength] + "..."
 }
@@ -0,0 +1,78 @@
+package modelGenerator
+
+import (
+	"github.com/Uffe-Code/go-odata/odataClient"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newMetadataTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch {
+		case request.URL.Path == "/html/$metadata":
+			writer.Header().Set("Content-Type", "text/html")
+			_, _ = writer.Write([]byte("<html><body>Sign in</body></html>"))
+		case request.URL.Path != "/$metadata":
+			writer.WriteHeader(404)
+		case request.Header.Get("Authorization") != "Bearer secret" || request.Header.Get("X-Tenant") != "42":
+			writer.WriteHeader(401)
+			_, _ = writer.Write([]byte("unauthorized"))
+		default:
+			writer.Header().Set("Content-Type", "application/xml")
+			_, _ = writer.Write([]byte(trippinEdmxSchema))
+		}
+	}))
+}
+
+func Test_fetchMetadata(t *testing.T) {
+	testServer := newMetadataTestServer()
+	defer testServer.Close()
+
+	headers := map[string]string{"Authorization": "Bearer secret", "X-Tenant": "42"}
+	metadata, err := fetchMetadata(testServer.Client(), testServer.URL+"/$metadata", headers)
+	assert.NoError(t, err)
+	assert.Equal(t, trippinEdmxSchema, string(metadata))
+
+	_, err = fetchMetadata(nil, testServer.URL+"/$metadata", nil)
+	assert.EqualError(t, err, "fetching "+testServer.URL+"/$metadata returned status 401: unauthorized")
+
+	_, err = fetchMetadata(nil, testServer.URL+"/html/$metadata", headers)
+	assert.ErrorContains(t, err, "returned text/html instead of an EDMX document")
+}
+
+func Test_Generator_fetch_metadata(t *testing.T) {
+	testServer := newMetadataTestServer()
+	defer testServer.Close()
+
+	expected, err := Generator{Metadata: []byte(trippinEdmxSchema), PackageName: "trippin"}.Generate()
+	assert.NoError(t, err)
+
+	fromUrl, err := Generator{
+		ApiUrl:      testServer.URL,
+		PackageName: "trippin",
+		Headers:     map[string]string{"Authorization": "Bearer secret", "X-Tenant": "42"},
+		HttpClient:  testServer.Client(),
+	}.Generate()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, fromUrl)
+
+	client := odataClient.New(testServer.URL)
+	client.AddHeader("Authorization", "Bearer secret")
+	client.AddHeader("X-Tenant", "42")
+	fromClient, err := Generator{Client: client, PackageName: "trippin"}.Generate()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, fromClient)
+
+	_, err = Generator{Client: odataClient.New(testServer.URL), PackageName: "trippin"}.Generate()
+	assert.True(t, odataClient.IsUnauthorized(err))
+}
+
+func Test_abbreviate(t *testing.T) {
+	assert.Equal(t, "unauthorized", abbreviate(" unauthorized\n", 20))
+	assert.Equal(t, "unauth...", abbreviate("unauthorized", 6))
+	assert.Equal(t, "Zugriff verweigert: ung...", abbreviate("Zugriff verweigert: ungültig", 24))
+	assert.Equal(t, "Zugriff verweigert: ungü...", abbreviate("Zugriff verweigert: ungültig", 25))
+	assert.Equal(t, "...", abbreviate("日本", 2))
+}
@@ -0,0 +1,113 @@
+package modelGenerator
+
+import (
+	"strings"
+)
+
+type rawEdmxOperation struct {
+	Name       string         `xml:"Name,attr"`
+	IsBound    string         `xml:"IsBound,attr"`
+	Parameters []edmxProperty `xml:"Parameter"`
+	ReturnType []edmxProperty `xml:"ReturnType"`
+}
+
+func (o rawEdmxOperation) toOperation(schema edmxSchema, isAction bool) edmxOperation {
+	operation := edmxOperation{
+		Name:      o.Name,
+		IsBound:   strings.ToLower(o.IsBound) == "true",
+		IsAction:  isAction,
+		namespace: schema.Namespace,
+	}
+	for _, parameter := range o.Parameters {
+		parameter.schema = schema
+		operation.Parameters = append(operation.Parameters, parameter)
+	}
+	for _, returnType := range o.ReturnType {
+		returnType.schema = schema
+		returnType.Nullable = "false"
+		operation.ReturnType = &returnType
+	}
+	return operation
+}
+
+// edmxOperation is either a function or an action
+type edmxOperation struct {
+	Name       string
+	IsBound    bool
+	IsAction   bool
+	Parameters []edmxProperty
+	ReturnType *edmxProperty
+	namespace  string
+	// overloadSuffix tells the generated functions of overloads bound to the same type apart, see nameOverloads
+	overloadSuffix string
+}
+
+func (o edmxOperation) qualifiedName() string {
+	return o.namespace + "." + o.Name
+}
+
+// bindingParameter is the first parameter of a bound operation, which is the entity or collection the operation is bound to
+func (o edmxOperation) bindingParameter() (edmxProperty, bool) {
+	if !o.IsBound || len(o.Parameters) == 0 {
+		return edmxProperty{}, false
+	}
+	return o.Parameters[0], true
+}
+
+// signature identifies an overload of the operation by its binding and parameters
+func (o edmxOperation) signature() string {
+	parameters := make([]string, len(o.Parameters))
+	for i, parameter := range o.Parameters {
+		parameters[i] = parameter.Name + " " + parameter.Type
+	}
+	return o.qualifiedName() + "(" + strings.Join(parameters, ", ") + ")"
+}
+
+// callParameters are the parameters which are passed when calling the operation
+func (o edmxOperation) callParameters() []edmxProperty {
+	if o.IsBound && len(o.Parameters) > 0 {
+		return o.Parameters[1:]
+	}
+	return o.Parameters
+}
+
+type rawEdmxOperationImport struct {
+	Name      string `xml:"Name,attr"`
+	Function  string `xml:"Function,attr"`
+	Action    string `xml:"Action,attr"`
+	EntitySet string `xml:"EntitySet,attr"`
+}
+
+func (i rawEdmxOperationImport) toOperationImport(schema edmxSchema) edmxOperationImport {
+	operationImport := edmxOperationImport{
+		schema:    schema,
+		Name:      i.Name,
+		Operation: i.Function,
+		IsAction:  i.Action != "",
+	}
+	if operationImport.IsAction {
+		operationImport.Operation = i.Action
+	}
+	return operationImport
+}
+
+// edmxOperationImport exposes an unbound operation at the service root
+type edmxOperationImport struct {
+	schema    edmxSchema
+	Name      string
+	Operation string
+	IsAction  bool
+}
+
+// getOperations returns the unbound operations which are imported, which are all overloads of the function
+func (i edmxOperationImport) getOperations() []edmxOperation {
+	qualifiedName := i.schema.dataService.normalizeQualifiedName(i.Operation)
+	namespace, _ := splitQualifiedName(qualifiedName)
+	var operations []edmxOperation
+	for _, operation := range i.schema.dataService.Schemas[namespace].Operations {
+		if operation.qualifiedName() == qualifiedName && !operation.IsBound && operation.IsAction == i.IsAction {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
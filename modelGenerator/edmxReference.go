@@ -0,0 +1,338 @@
+package modelGenerator
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type rawEdmxReference struct {
+	Uri      string           `xml:"Uri,attr"`
+	Includes []rawEdmxInclude `xml:"Include"`
+}
+
+type rawEdmxInclude struct {
+	Namespace string `xml:"Namespace,attr"`
+	Alias     string `xml:"Alias,attr"`
+}
+
+// referenceLoader loads the documents referenced with edmx:Reference. Fetched documents are kept in memory, and
+// saved in the directory when it is set, so they are only fetched once.
+type referenceLoader struct {
+	// baseUri is the url or file path of the main document, which relative references are resolved against
+	baseUri string
+	// directory holds the referenced documents, named after the first namespace included from the reference
+	directory string
+	fetch     func(url string) ([]byte, error)
+	documents map[string]edmxXmlData
+}
+
+func newReferenceLoader(baseUri string, directory string, fetch func(url string) ([]byte, error)) *referenceLoader {
+	return &referenceLoader{
+		baseUri:   baseUri,
+		directory: directory,
+		fetch:     fetch,
+		documents: map[string]edmxXmlData{},
+	}
+}
+
+func isHttpUrl(uri string) bool {
+	parsed, err := url.Parse(uri)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https")
+}
+
+// resolveUri resolves the reference against the url or file path of the document containing it
+func resolveUri(baseUri string, reference string) (string, error) {
+	if isHttpUrl(reference) {
+		return reference, nil
+	}
+	if isHttpUrl(baseUri) {
+		base, err := url.Parse(baseUri)
+		if err != nil {
+			return "", err
+		}
+		relative, err := url.Parse(reference)
+		if err != nil {
+			return "", err
+		}
+		return base.ResolveReference(relative).String(), nil
+	}
+	if baseUri == "" || filepath.IsAbs(reference) {
+		return reference, nil
+	}
+	return filepath.Join(filepath.Dir(baseUri), filepath.FromSlash(reference)), nil
+}
+
+// load returns the referenced document, from the memory cache, the directory, the url or the local file
+func (l *referenceLoader) load(uri string, namespace string) (edmxXmlData, error) {
+	if document, ok := l.documents[uri]; ok {
+		return document, nil
+	}
+
+	var xmlData []byte
+	var err error
+	cacheFile := ""
+	if l.directory != "" {
+		cacheFile = filepath.Join(l.directory, namespace+".xml")
+		xmlData, err = ioutil.ReadFile(cacheFile)
+	}
+	if cacheFile == "" || errors.Is(err, os.ErrNotExist) {
+		if isHttpUrl(uri) {
+			xmlData, err = l.fetch(uri)
+			if err == nil && cacheFile != "" {
+				if err = os.MkdirAll(l.directory, 0755); err == nil {
+					err = ioutil.WriteFile(cacheFile, xmlData, 0644)
+				}
+			}
+		} else {
+			xmlData, err = ioutil.ReadFile(uri)
+		}
+	}
+	if err != nil {
+		return edmxXmlData{}, fmt.Errorf("loading reference %s: %w", uri, err)
+	}
+
+	document, err := parseEdmxDocument(xmlData)
+	if err != nil {
+		return edmxXmlData{}, fmt.Errorf("parsing reference %s: %w", uri, err)
+	}
+	l.documents[uri] = document
+	return document, nil
+}
+
+// usedNamespaces returns the namespaces and aliases of all types which are referenced by the schemas
+func usedNamespaces(schemas []rawEdmxSchema) map[string]bool {
+	namespaces := map[string]bool{}
+	use := func(typeName string) {
+		if namespace, _ := splitQualifiedName(trimCollection(typeName)); namespace != "" {
+			namespaces[namespace] = true
+		}
+	}
+	useTypes := func(types []rawEdmxEntityType) {
+		for _, structuredType := range types {
+			use(structuredType.BaseType)
+			for _, property := range structuredType.Properties {
+				use(property.Type)
+			}
+			for _, navigationProperty := range structuredType.NavigationProperties {
+				use(navigationProperty.Type)
+			}
+		}
+	}
+	for _, schema := range schemas {
+		useTypes(schema.EntityTypes)
+		useTypes(schema.ComplexTypes)
+		for _, operation := range append(append([]rawEdmxOperation{}, schema.Functions...), schema.Actions...) {
+			for _, parameter := range append(append([]edmxProperty{}, operation.Parameters...), operation.ReturnType...) {
+				use(parameter.Type)
+			}
+		}
+		for _, container := range schema.Containers {
+			for _, entitySet := range container.EntitySets {
+				use(entitySet.EntityType)
+			}
+			for _, singleton := range container.Singletons {
+				use(singleton.Type)
+			}
+		}
+	}
+	return namespaces
+}
+
+func parseEdmxDocument(xmlData []byte) (edmxXmlData, error) {
+	var edmxData edmxXmlData
+	err := xml.Unmarshal(xmlData, &edmxData)
+	if err != nil {
+		var apiErr apiErrorMessage
+		err2 := xml.Unmarshal(xmlData, &apiErr)
+		if err2 == nil {
+			return edmxXmlData{}, fmt.Errorf("error from API: %s", apiErr.Message)
+		}
+		return edmxXmlData{}, err
+	}
+
+	if edmxData.Version != "4.0" {
+		return edmxXmlData{}, fmt.Errorf("only version 4.0 is supported, got %s", edmxData.Version)
+	}
+
+	if len(edmxData.DataServices) != 1 {
+		return edmxXmlData{}, fmt.Errorf("unexpected amount of <edmx:DataServices> in Edmx source, got %d and expected 1", len(edmxData.DataServices))
+	}
+	return edmxData, nil
+}
+
+func parseEdmx(xmlData []byte) (edmxDataServices, error) {
+	return parseEdmxWithReferences(xmlData, nil)
+}
+
+// parseEdmxWithReferences parses the document together with the schemas it includes from referenced documents.
+// References are only followed when a type of an included namespace is used, so vocabularies are not loaded.
+// Without a loader the references are not followed, and types of referenced namespaces are not resolved.
+func parseEdmxWithReferences(xmlData []byte, loader *referenceLoader) (edmxDataServices, error) {
+	document, err := parseEdmxDocument(xmlData)
+	if err != nil {
+		return edmxDataServices{}, err
+	}
+
+	type pendingDocument struct {
+		document edmxXmlData
+		uri      string
+		schemas  []rawEdmxSchema
+	}
+	schemas := document.DataServices[0].Schemas
+	loadedNamespaces := map[string]bool{}
+	for _, schema := range schemas {
+		loadedNamespaces[schema.Namespace] = true
+	}
+	aliases := documentAliases(document)
+	baseUri := ""
+	if loader != nil {
+		baseUri = loader.baseUri
+	}
+
+	pending := []pendingDocument{{document: document, uri: baseUri, schemas: schemas}}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		used := usedNamespaces(current.schemas)
+		for _, reference := range current.document.References {
+			needed := false
+			for _, include := range reference.Includes {
+				if !loadedNamespaces[include.Namespace] && (used[include.Namespace] || used[include.Alias]) {
+					needed = true
+				}
+			}
+			if !needed || loader == nil {
+				continue
+			}
+
+			uri, err := resolveUri(current.uri, reference.Uri)
+			if err != nil {
+				return edmxDataServices{}, err
+			}
+			referencedDocument, err := loader.load(uri, reference.Includes[0].Namespace)
+			if err != nil {
+				return edmxDataServices{}, err
+			}
+			var includedSchemas []rawEdmxSchema
+			referencedAliases := documentAliases(referencedDocument)
+			for _, include := range reference.Includes {
+				for _, schema := range referencedDocument.DataServices[0].Schemas {
+					if schema.Namespace == include.Namespace && !loadedNamespaces[schema.Namespace] {
+						loadedNamespaces[schema.Namespace] = true
+						includedSchemas = append(includedSchemas, schema.qualifyTypeNames(referencedAliases))
+					}
+				}
+			}
+			schemas = append(schemas, includedSchemas...)
+			pending = append(pending, pendingDocument{document: referencedDocument, uri: uri, schemas: includedSchemas})
+		}
+	}
+
+	return toDataService(schemas, aliases), nil
+}
+
+// documentAliases maps the aliases of the namespaces included by the document, and of its schemas, to the namespaces.
+// Aliases only apply within the document which declares them.
+func documentAliases(document edmxXmlData) map[string]string {
+	aliases := map[string]string{}
+	for _, reference := range document.References {
+		for _, include := range reference.Includes {
+			if _, ok := aliases[include.Alias]; include.Alias != "" && !ok {
+				aliases[include.Alias] = include.Namespace
+			}
+		}
+	}
+	for _, dataServices := range document.DataServices {
+		for _, schema := range dataServices.Schemas {
+			if _, ok := aliases[schema.Alias]; schema.Alias != "" && !ok {
+				aliases[schema.Alias] = schema.Namespace
+			}
+		}
+	}
+	return aliases
+}
+
+// qualifyTypeNames returns the schema with the aliases in its type names replaced by the namespaces, so the schema
+// of a referenced document can be used together with documents which use the same alias for another namespace
+func (s rawEdmxSchema) qualifyTypeNames(aliases map[string]string) rawEdmxSchema {
+	qualify := func(typeName string) string {
+		qualifiedName := trimCollection(typeName)
+		alias, name := splitQualifiedName(qualifiedName)
+		if namespace, ok := aliases[alias]; ok {
+			return strings.Replace(typeName, qualifiedName, namespace+"."+name, 1)
+		}
+		return typeName
+	}
+	qualifyProperties := func(properties []edmxProperty) []edmxProperty {
+		qualified := make([]edmxProperty, len(properties))
+		for i, property := range properties {
+			property.Type = qualify(property.Type)
+			qualified[i] = property
+		}
+		return qualified
+	}
+	qualifyTypes := func(types []rawEdmxEntityType) []rawEdmxEntityType {
+		qualified := make([]rawEdmxEntityType, len(types))
+		for i, structuredType := range types {
+			structuredType.BaseType = qualify(structuredType.BaseType)
+			structuredType.Properties = qualifyProperties(structuredType.Properties)
+			navigationProperties := make([]edmxNavigationProperty, len(structuredType.NavigationProperties))
+			for j, navigationProperty := range structuredType.NavigationProperties {
+				navigationProperty.Type = qualify(navigationProperty.Type)
+				navigationProperties[j] = navigationProperty
+			}
+			structuredType.NavigationProperties = navigationProperties
+			qualified[i] = structuredType
+		}
+		return qualified
+	}
+	qualifyOperations := func(operations []rawEdmxOperation) []rawEdmxOperation {
+		qualified := make([]rawEdmxOperation, len(operations))
+		for i, operation := range operations {
+			operation.Parameters = qualifyProperties(operation.Parameters)
+			operation.ReturnType = qualifyProperties(operation.ReturnType)
+			qualified[i] = operation
+		}
+		return qualified
+	}
+
+	s.EntityTypes = qualifyTypes(s.EntityTypes)
+	s.ComplexTypes = qualifyTypes(s.ComplexTypes)
+	s.Functions = qualifyOperations(s.Functions)
+	s.Actions = qualifyOperations(s.Actions)
+	containers := make([]rawEdmxContainer, len(s.Containers))
+	for i, container := range s.Containers {
+		entitySets := make([]rawEdmxEntitySet, len(container.EntitySets))
+		for j, entitySet := range container.EntitySets {
+			entitySet.EntityType = qualify(entitySet.EntityType)
+			entitySets[j] = entitySet
+		}
+		singletons := make([]rawEdmxSingleton, len(container.Singletons))
+		for j, singleton := range container.Singletons {
+			singleton.Type = qualify(singleton.Type)
+			singletons[j] = singleton
+		}
+		operationImports := func(imports []rawEdmxOperationImport) []rawEdmxOperationImport {
+			qualified := make([]rawEdmxOperationImport, len(imports))
+			for j, operationImport := range imports {
+				operationImport.Function = qualify(operationImport.Function)
+				operationImport.Action = qualify(operationImport.Action)
+				qualified[j] = operationImport
+			}
+			return qualified
+		}
+		container.EntitySets = entitySets
+		container.Singletons = singletons
+		container.FunctionImports = operationImports(container.FunctionImports)
+		container.ActionImports = operationImports(container.ActionImports)
+		containers[i] = container
+	}
+	s.Containers = containers
+	return s
+}
@@ -0,0 +1,222 @@
+package modelGenerator
+
+import (
+	"github.com/Uffe-Code/go-odata/odataClient"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const referencingEdmxSchema = `<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:Reference Uri="https://oasis-tcs.github.io/odata-vocabularies/vocabularies/Org.OData.Core.V1.xml">
+<edmx:Include Namespace="Org.OData.Core.V1" Alias="Core"/>
+</edmx:Reference>
+<edmx:Reference Uri="common/Common.xml">
+<edmx:Include Namespace="Shop.Common" Alias="Common"/>
+</edmx:Reference>
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EntityType Name="Customer">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+<Property Name="Address" Type="Common.Address"/>
+<Property Name="Status" Type="Shop.Common.Status" Nullable="false"/>
+</EntityType>
+<EntityContainer Name="Container">
+<EntitySet Name="Customers" EntityType="Shop.Customer"/>
+</EntityContainer>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`
+
+const commonEdmxSchema = `<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:Reference Uri="../geo/Geo.xml">
+<edmx:Include Namespace="Shop.Geo" Alias="Geo"/>
+</edmx:Reference>
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Common">
+<ComplexType Name="Address">
+<Property Name="Street" Type="Edm.String"/>
+<Property Name="Location" Type="Geo.Location"/>
+</ComplexType>
+<EnumType Name="Status">
+<Member Name="Active" Value="0"/>
+<Member Name="Blocked" Value="1"/>
+</EnumType>
+</Schema>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Unused">
+<ComplexType Name="Unused"/>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`
+
+const geoEdmxSchema = `<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Geo">
+<ComplexType Name="Location">
+<Property Name="Latitude" Type="Edm.Double" Nullable="false"/>
+<Property Name="Longitude" Type="Edm.Double" Nullable="false"/>
+</ComplexType>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`
+
+var referenceTestDocuments = map[string]string{
+	"/service/$metadata":         referencingEdmxSchema,
+	"/service/common/Common.xml": commonEdmxSchema,
+	"/service/geo/Geo.xml":       geoEdmxSchema,
+}
+
+func newReferenceTestServer(requests map[string]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests[request.URL.Path]++
+		document, ok := referenceTestDocuments[request.URL.Path]
+		if !ok {
+			writer.WriteHeader(404)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/xml")
+		_, _ = writer.Write([]byte(document))
+	}))
+}
+
+func writeTestFile(filePath string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, []byte(content), 0644)
+}
+
+func Test_parseEdmxWithReferences_local_files(t *testing.T) {
+	directory := t.TempDir()
+	assert.NoError(t, writeTestFile(filepath.Join(directory, "service", "metadata.xml"), referencingEdmxSchema))
+	assert.NoError(t, writeTestFile(filepath.Join(directory, "service", "common", "Common.xml"), commonEdmxSchema))
+	assert.NoError(t, writeTestFile(filepath.Join(directory, "service", "geo", "Geo.xml"), geoEdmxSchema))
+
+	code, err := Generator{MetadataFile: filepath.Join(directory, "service", "metadata.xml"), PackageName: "shop"}.Generate()
+	assert.NoError(t, err)
+	assert.Contains(t, code, "Address nullable.Nullable[Address] `json:\"Address\"`")
+	assert.Contains(t, code, "Status  Status                     `json:\"Status\"`")
+	assert.Contains(t, code, "Location nullable.Nullable[Location] `json:\"Location\"`")
+	assert.NotContains(t, code, "type Unused struct")
+}
+
+func Test_parseEdmxWithReferences_aliases_per_document(t *testing.T) {
+	directory := t.TempDir()
+	document := func(references string, schema string) string {
+		return `<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">` + references +
+			`<edmx:DataServices><Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" ` + schema + `</Schema></edmx:DataServices></edmx:Edmx>`
+	}
+	reference := func(uri string, namespace string, alias string) string {
+		return `<edmx:Reference Uri="` + uri + `"><edmx:Include Namespace="` + namespace + `" Alias="` + alias + `"/></edmx:Reference>`
+	}
+	files := map[string]string{
+		"metadata.xml": document(reference("a.xml", "Shop.A", "A")+reference("b.xml", "Shop.B", "B"), `Namespace="Shop">
+<ComplexType Name="Order"><Property Name="A" Type="A.Item"/><Property Name="B" Type="B.Item"/></ComplexType>`),
+		"a.xml": document(reference("x.xml", "Shop.X", "T"), `Namespace="Shop.A">
+<ComplexType Name="Item"><Property Name="Thing" Type="T.Thing"/></ComplexType>`),
+		"b.xml": document(reference("y.xml", "Shop.Y", "T"), `Namespace="Shop.B">
+<ComplexType Name="Item"><Property Name="Thing" Type="T.Thing"/></ComplexType>`),
+		"x.xml": document("", `Namespace="Shop.X"><ComplexType Name="Thing"/>`),
+		"y.xml": document("", `Namespace="Shop.Y"><ComplexType Name="Thing"/>`),
+	}
+	for name, content := range files {
+		assert.NoError(t, writeTestFile(filepath.Join(directory, name), content))
+	}
+
+	code, err := Generator{MetadataFile: filepath.Join(directory, "metadata.xml"), PackageName: "shop"}.Generate()
+	assert.NoError(t, err)
+	assert.Regexp(t, "type AItem struct {\n\tThing nullable.Nullable\\[XThing\\]", code)
+	assert.Regexp(t, "type BItem struct {\n\tThing nullable.Nullable\\[YThing\\]", code)
+}
+
+func Test_parseEdmxWithReferences_without_loader(t *testing.T) {
+	ds, err := parseEdmx([]byte(referencingEdmxSchema))
+	assert.NoError(t, err)
+	assert.Len(t, ds.Schemas, 1)
+	assert.Equal(t, "Shop.Common.Address", ds.normalizeQualifiedName("Common.Address"))
+	assert.Equal(t, "nullable.Nullable[interface{}]", ds.Schemas["Shop"].EntityTypes["Customer"].Properties["Address"].goType())
+}
+
+func Test_parseEdmxWithReferences_cache(t *testing.T) {
+	requests := map[string]int{}
+	testServer := newReferenceTestServer(requests)
+	defer testServer.Close()
+	directory := filepath.Join(t.TempDir(), "references")
+
+	generator := Generator{ApiUrl: testServer.URL + "/service", PackageName: "shop", ReferenceDirectory: directory}
+	code, err := generator.Generate()
+	assert.NoError(t, err)
+	assert.Contains(t, code, "type Location struct")
+	assert.Equal(t, map[string]int{"/service/$metadata": 1, "/service/common/Common.xml": 1, "/service/geo/Geo.xml": 1}, requests)
+
+	cached, err := os.ReadFile(filepath.Join(directory, "Shop.Common.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, commonEdmxSchema, string(cached))
+
+	cachedCode, err := generator.Generate()
+	assert.NoError(t, err)
+	assert.Equal(t, code, cachedCode)
+	assert.Equal(t, map[string]int{"/service/$metadata": 2, "/service/common/Common.xml": 1, "/service/geo/Geo.xml": 1}, requests)
+}
+
+func Test_parseEdmxWithReferences_client(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		document, ok := referenceTestDocuments[request.URL.Path]
+		if request.Header.Get("Authorization") != "Bearer secret" {
+			writer.WriteHeader(401)
+			return
+		} else if !ok {
+			writer.WriteHeader(404)
+			return
+		}
+		_, _ = writer.Write([]byte(document))
+	}))
+	defer testServer.Close()
+
+	client := odataClient.New(testServer.URL + "/service")
+	client.AddHeader("Authorization", "Bearer secret")
+	code, err := Generator{Client: client, PackageName: "shop"}.Generate()
+	assert.NoError(t, err)
+	assert.Contains(t, code, "type Address struct")
+	assert.Contains(t, code, "type Location struct")
+}
+
+func Test_referenceLoader_memory_cache(t *testing.T) {
+	fetched := 0
+	loader := newReferenceLoader("https://example.com/service/$metadata", "", func(url string) ([]byte, error) {
+		fetched++
+		assert.Equal(t, "https://example.com/service/common/Common.xml", url)
+		return []byte(commonEdmxSchema), nil
+	})
+	for i := 0; i < 2; i++ {
+		document, err := loader.load("https://example.com/service/common/Common.xml", "Shop.Common")
+		assert.NoError(t, err)
+		assert.Len(t, document.DataServices[0].Schemas, 2)
+	}
+	assert.Equal(t, 1, fetched)
+}
+
+func Test_resolveUri(t *testing.T) {
+	tests := []struct {
+		base      string
+		reference string
+		expected  string
+	}{
+		{"https://example.com/service/$metadata", "common/Common.xml", "https://example.com/service/common/Common.xml"},
+		{"https://example.com/service/$metadata", "/vocabularies/Core.xml", "https://example.com/vocabularies/Core.xml"},
+		{"https://example.com/service/$metadata", "https://example.org/Core.xml", "https://example.org/Core.xml"},
+		{filepath.Join("models", "metadata.xml"), "../geo/Geo.xml", "geo/Geo.xml"},
+		{"", "Common.xml", "Common.xml"},
+	}
+	for _, test := range tests {
+		uri, err := resolveUri(test.base, test.reference)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.FromSlash(test.expected), filepath.FromSlash(uri), test.reference)
+	}
+}
@@ -0,0 +1,213 @@
+package modelGenerator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// nameFilter selects the parts of the data service to generate code for. The patterns use the syntax of path.Match,
+// and are matched against both the name and the namespace qualified name, e.g. People or Trippin.Person.
+type nameFilter struct {
+	include []string
+	exclude []string
+}
+
+func newNameFilter(include []string, exclude []string) (nameFilter, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nameFilter{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nameFilter{include: include, exclude: exclude}, nil
+}
+
+func matchesAny(patterns []string, names ...string) bool {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if matched, _ := path.Match(pattern, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f nameFilter) isExcluded(names ...string) bool {
+	return matchesAny(f.exclude, names...)
+}
+
+func (f nameFilter) isIncluded(names ...string) bool {
+	return len(f.include) == 0 || matchesAny(f.include, names...)
+}
+
+// apply removes the excluded parts from the data service. When include patterns are given, only the matching
+// entity sets, singletons, operation imports and types are kept, together with the types they depend on.
+// The maps of the schemas are shared by all copies of the schema, so the data service is changed in place.
+func (f nameFilter) apply(dataService edmxDataServices) {
+	for namespace, schema := range dataService.Schemas {
+		dataService.Schemas[namespace] = f.removeExcluded(schema)
+	}
+	if len(f.include) == 0 {
+		return
+	}
+
+	usedTypes := map[string]bool{}
+	var pending []string
+	useType := func(typeName string) {
+		typeName = dataService.normalizeQualifiedName(trimCollection(typeName))
+		if !usedTypes[typeName] {
+			usedTypes[typeName] = true
+			pending = append(pending, typeName)
+		}
+	}
+	useOperation := func(operation edmxOperation) {
+		for _, parameter := range operation.Parameters {
+			useType(parameter.Type)
+		}
+		if operation.ReturnType != nil {
+			useType(operation.ReturnType.Type)
+		}
+	}
+
+	for _, namespace := range sortedKeys(dataService.Schemas) {
+		schema := dataService.Schemas[namespace]
+		for name, set := range schema.EntitySets {
+			if f.isIncluded(name, namespace+"."+name) {
+				useType(set.EntityType)
+			} else {
+				delete(schema.EntitySets, name)
+			}
+		}
+		for name, singleton := range schema.Singletons {
+			if f.isIncluded(name, namespace+"."+name) {
+				useType(singleton.EntityType)
+			} else {
+				delete(schema.Singletons, name)
+			}
+		}
+		var operationImports []edmxOperationImport
+		for _, operationImport := range schema.OperationImports {
+			if f.isIncluded(operationImport.Name, namespace+"."+operationImport.Name) {
+				operationImports = append(operationImports, operationImport)
+				for _, operation := range operationImport.getOperations() {
+					useOperation(operation)
+				}
+			}
+		}
+		schema.OperationImports = operationImports
+		dataService.Schemas[namespace] = schema
+
+		for _, types := range []map[string]edmxEntityType{schema.EntityTypes, schema.ComplexTypes} {
+			for name, structuredType := range types {
+				if matchesAny(f.include, name, structuredType.qualifiedName()) {
+					useType(structuredType.qualifiedName())
+				}
+			}
+		}
+		for name, enumType := range schema.EnumTypes {
+			if matchesAny(f.include, name, enumType.qualifiedName()) {
+				useType(enumType.qualifiedName())
+			}
+		}
+	}
+
+	for len(pending) > 0 {
+		typeName := pending[0]
+		pending = pending[1:]
+		structuredType, ok := dataService.lookupStructuredType(typeName)
+		if !ok {
+			continue
+		}
+		if structuredType.BaseType != "" {
+			useType(structuredType.BaseType)
+		}
+		for _, property := range structuredType.Properties {
+			useType(property.Type)
+		}
+		for _, navigationProperty := range structuredType.NavigationProperties {
+			useType(navigationProperty.Type)
+		}
+		for _, schema := range dataService.Schemas {
+			for _, operation := range schema.Operations {
+				if bindingParameter, ok := operation.bindingParameter(); ok && dataService.normalizeQualifiedName(trimCollection(bindingParameter.Type)) == typeName {
+					useOperation(operation)
+				}
+			}
+		}
+	}
+
+	for namespace, schema := range dataService.Schemas {
+		for _, types := range []map[string]edmxEntityType{schema.EntityTypes, schema.ComplexTypes} {
+			for name := range types {
+				if !usedTypes[namespace+"."+name] {
+					delete(types, name)
+				}
+			}
+		}
+		for name := range schema.EnumTypes {
+			if !usedTypes[namespace+"."+name] {
+				delete(schema.EnumTypes, name)
+			}
+		}
+	}
+}
+
+func trimCollection(typeName string) string {
+	if strings.HasPrefix(typeName, "Collection(") {
+		return typeName[11 : len(typeName)-1]
+	}
+	return typeName
+}
+
+func (f nameFilter) removeExcluded(schema edmxSchema) edmxSchema {
+	if len(f.exclude) == 0 {
+		return schema
+	}
+	namespace := schema.Namespace
+	for _, names := range []map[string]edmxEntitySet{schema.EntitySets, schema.Singletons} {
+		for name := range names {
+			if f.isExcluded(name, namespace+"."+name) {
+				delete(names, name)
+			}
+		}
+	}
+	for _, types := range []map[string]edmxEntityType{schema.EntityTypes, schema.ComplexTypes} {
+		for name := range types {
+			if f.isExcluded(name, namespace+"."+name) {
+				delete(types, name)
+			}
+		}
+	}
+	for name := range schema.EnumTypes {
+		if f.isExcluded(name, namespace+"."+name) {
+			delete(schema.EnumTypes, name)
+		}
+	}
+	for name, set := range schema.EntitySets {
+		if _, ok := schema.dataService.lookupStructuredType(set.EntityType); !ok {
+			delete(schema.EntitySets, name)
+		}
+	}
+	for name, singleton := range schema.Singletons {
+		if _, ok := schema.dataService.lookupStructuredType(singleton.EntityType); !ok {
+			delete(schema.Singletons, name)
+		}
+	}
+
+	var operations []edmxOperation
+	for _, operation := range schema.Operations {
+		if !f.isExcluded(operation.Name, operation.qualifiedName()) {
+			operations = append(operations, operation)
+		}
+	}
+	schema.Operations = operations
+	var operationImports []edmxOperationImport
+	for _, operationImport := range schema.OperationImports {
+		if !f.isExcluded(operationImport.Name, namespace+"."+operationImport.Name) {
+			operationImports = append(operationImports, operationImport)
+		}
+	}
+	schema.OperationImports = operationImports
+	return schema
+}
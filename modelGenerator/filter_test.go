@@ -0,0 +1,103 @@
+package modelGenerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_nameFilter_exclude(t *testing.T) {
+	ds, _ := parseEdmx([]byte(trippinEdmxSchema))
+	filter, err := newNameFilter(nil, []string{"Trippin.Manager", "Airports", "Get*"})
+	assert.NoError(t, err)
+	filter.apply(ds)
+	schema := ds.Schemas["Trippin"]
+
+	assert.NotContains(t, schema.EntityTypes, "Manager")
+	assert.Contains(t, schema.EntityTypes, "Employee")
+	assert.NotContains(t, schema.EntitySets, "Airports")
+	assert.Contains(t, schema.EntitySets, "People")
+	for _, operation := range schema.Operations {
+		assert.NotContains(t, operation.Name, "Get")
+	}
+	assert.Len(t, schema.OperationImports, 1)
+	assert.Equal(t, "ResetDataSource", schema.OperationImports[0].Name)
+}
+
+func Test_nameFilter_include(t *testing.T) {
+	ds, _ := parseEdmx([]byte(trippinEdmxSchema))
+	filter, err := newNameFilter([]string{"Airlines", "GetNearestAirport"}, nil)
+	assert.NoError(t, err)
+	filter.apply(ds)
+	schema := ds.Schemas["Trippin"]
+
+	assert.Equal(t, []string{"Airlines"}, sortedKeys(schema.EntitySets))
+	assert.Empty(t, schema.Singletons)
+	assert.Equal(t, []string{"Airline", "Airport"}, sortedKeys(schema.EntityTypes))
+	assert.Equal(t, []string{"AirportLocation", "City", "Location"}, sortedKeys(schema.ComplexTypes))
+	assert.Empty(t, schema.EnumTypes)
+	assert.Len(t, schema.OperationImports, 1)
+
+	code, err := generateCodeFromSchema("trippin", ds)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "func GetNearestAirport(")
+	assert.NotContains(t, code, "type Person struct")
+}
+
+func Test_nameFilter_include_dependencies(t *testing.T) {
+	ds, _ := parseEdmx([]byte(trippinEdmxSchema))
+	filter, err := newNameFilter([]string{"Me"}, []string{"Trippin.Trip"})
+	assert.NoError(t, err)
+	filter.apply(ds)
+	schema := ds.Schemas["Trippin"]
+
+	assert.Empty(t, schema.EntitySets)
+	assert.Contains(t, schema.Singletons, "Me")
+	assert.Contains(t, schema.EntityTypes, "Person")
+	assert.Contains(t, schema.EntityTypes, "Airline")
+	assert.NotContains(t, schema.EntityTypes, "Trip")
+	assert.Contains(t, schema.EnumTypes, "PersonGender")
+
+	code, err := generateCodeFromSchema("trippin", ds)
+	assert.NoError(t, err)
+	assert.Regexp(t, `Trips +\[\]interface{} +`+"`"+`json:"Trips,omitempty"`, code)
+}
+
+func Test_nameFilter_invalid_pattern(t *testing.T) {
+	_, err := newNameFilter([]string{"People["}, nil)
+	assert.Error(t, err)
+}
+
+func Test_nameFilter_names_after_filtering(t *testing.T) {
+	metadata := []byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Sales">
+<ComplexType Name="Address"><Property Name="Street" Type="Edm.String"/></ComplexType>
+<EntityType Name="Order">
+<Key><PropertyRef Name="Id"/></Key>
+<Property Name="Id" Type="Edm.Int32" Nullable="false"/>
+<Property Name="DeliveryAddress" Type="Shop.Sales.Address"/>
+</EntityType>
+<EntityContainer Name="Container"><EntitySet Name="Orders" EntityType="Shop.Sales.Order"/></EntityContainer>
+</Schema>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Billing">
+<ComplexType Name="Address"><Property Name="Iban" Type="Edm.String"/></ComplexType>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`)
+
+	code, err := Generator{PackageName: "shop", Exclude: []string{"Shop.Billing.Address"}}.generateFromMetadata(metadata)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "type Address struct")
+	assert.Regexp(t, `DeliveryAddress +nullable.Nullable\[Address\]`, code)
+	assert.NotContains(t, code, "SalesAddress")
+
+	code, err = Generator{PackageName: "shop", Include: []string{"Orders"}}.generateFromMetadata(metadata)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "type Address struct")
+	assert.NotContains(t, code, "Iban")
+
+	code, err = Generator{PackageName: "shop"}.generateFromMetadata(metadata)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "type SalesAddress struct")
+	assert.Contains(t, code, "type BillingAddress struct")
+}
@@ -1,40 +1,230 @@
 package modelGenerator
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"github.com/Uffe-Code/go-odata/odataClient"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+const defaultFileName = "modelDefinitions.go"
+
+// ErrCodeOutdated is returned by CheckCode when the generated file differs from the code which would be generated
+var ErrCodeOutdated = errors.New("generated code is out of date")
+
 type Generator struct {
 	ApiUrl        string
 	DirectoryPath string
+	// MetadataFile is a local EDMX file which is used instead of fetching $metadata from the ApiUrl
+	MetadataFile string
+	// MetadataReader is read for the EDMX document instead of fetching $metadata. It can only be read once.
+	MetadataReader io.Reader
+	// Metadata is the EDMX document, which is used instead of fetching $metadata
+	Metadata []byte
+	// SaveMetadataFile is the file name in the directory where GenerateCode saves the EDMX document the code was
+	// generated from, so the code can be regenerated offline with MetadataFile
+	SaveMetadataFile string
+	// PackageName of the generated code, defaults to the name of the directory
+	PackageName string
+	// FileName of the generated code, defaults to modelDefinitions.go
+	FileName string
+	// Headers are added to the request for $metadata, e.g. for authorization
+	Headers map[string]string
+	// HttpClient is used to fetch $metadata from the ApiUrl, defaults to http.DefaultClient
+	HttpClient *http.Client
+	// Client fetches $metadata and its references instead of the ApiUrl, using the base url, headers and HTTP client
+	// of the client
+	Client odataClient.ODataClient
+	// Include limits the generated code to the entity sets, singletons, operation imports and types matching
+	// the patterns, and the types they depend on. The patterns use the syntax of path.Match.
+	Include []string
+	// Exclude removes the entity sets, singletons, operations and types matching the patterns
+	Exclude []string
+	// ReferenceDirectory caches the documents referenced with edmx:Reference, named after the first included
+	// namespace, e.g. Org.OData.Core.V1.xml. Documents found in the directory are not fetched again.
+	ReferenceDirectory string
+	// Config renames types and properties, replaces Go types and sets the nullable strategy
+	Config *Config
+	// ConfigFile is a YAML or JSON file with the Config, which is loaded when Config is not set
+	ConfigFile string
 }
 
 func (g Generator) metadataUrl() string {
 	return strings.TrimRight(g.ApiUrl, "/") + "/$metadata"
 }
 
-func (g Generator) GenerateCode() error {
+func (g Generator) filePath() (string, error) {
 	dirPath, err := filepath.Abs(g.DirectoryPath)
+	if err != nil {
+		return "", err
+	}
+	fileName := g.FileName
+	if fileName == "" {
+		fileName = defaultFileName
+	}
+	return filepath.Join(dirPath, fileName), nil
+}
+
+func (g Generator) packageName() (string, error) {
+	if g.PackageName != "" {
+		return g.PackageName, nil
+	}
+	dirPath, err := filepath.Abs(g.DirectoryPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(dirPath), nil
+}
+
+// loadMetadata returns the EDMX document from the first of Metadata, MetadataReader and MetadataFile which is set,
+// or fetches $metadata with the Client or from the ApiUrl
+func (g Generator) loadMetadata() ([]byte, error) {
+	switch {
+	case g.Metadata != nil:
+		return g.Metadata, nil
+	case g.MetadataReader != nil:
+		return ioutil.ReadAll(g.MetadataReader)
+	case g.MetadataFile != "":
+		return ioutil.ReadFile(g.MetadataFile)
+	case g.Client != nil:
+		return g.Client.Metadata(context.Background())
+	case g.ApiUrl != "":
+		return fetchMetadata(g.HttpClient, g.metadataUrl(), g.Headers)
+	}
+	return nil, errors.New("no metadata source, set either ApiUrl, Client, MetadataFile, MetadataReader or Metadata")
+}
+
+// metadataUri is the url or file path of the EDMX document, which relative references are resolved against
+func (g Generator) metadataUri() string {
+	switch {
+	case g.Metadata != nil, g.MetadataReader != nil:
+		return ""
+	case g.MetadataFile != "":
+		if filePath, err := filepath.Abs(g.MetadataFile); err == nil {
+			return filePath
+		}
+		return g.MetadataFile
+	case g.Client != nil:
+		return g.Client.MetadataUrl()
+	case g.ApiUrl != "":
+		return g.metadataUrl()
+	}
+	return ""
+}
+
+// referenceLoader fetches references with the Client when the metadata comes from it, so they are fetched
+// with the headers of the client
+func (g Generator) referenceLoader() *referenceLoader {
+	return newReferenceLoader(g.metadataUri(), g.ReferenceDirectory, func(url string) ([]byte, error) {
+		if g.Metadata == nil && g.MetadataReader == nil && g.MetadataFile == "" && g.Client != nil {
+			return g.Client.MetadataDocument(context.Background(), url)
+		}
+		return fetchMetadata(g.HttpClient, url, g.Headers)
+	})
+}
+
+// config returns the Config, or loads it from the ConfigFile
+func (g Generator) config() (Config, error) {
+	switch {
+	case g.Config != nil:
+		return *g.Config, nil
+	case g.ConfigFile != "":
+		return LoadConfig(g.ConfigFile)
+	}
+	return Config{}, nil
+}
+
+func (g Generator) generateFromMetadata(xmlData []byte) (string, error) {
+	config, err := g.config()
+	if err != nil {
+		return "", err
+	}
+	filter, err := newNameFilter(append(append([]string{}, g.Include...), config.Include...), append(append([]string{}, g.Exclude...), config.Exclude...))
+	if err != nil {
+		return "", err
+	}
+	if g.PackageName == "" {
+		g.PackageName = config.Package
+	}
+	packageName, err := g.packageName()
+	if err != nil {
+		return "", err
+	}
+
+	edmx, err := parseEdmxWithReferences(xmlData, g.referenceLoader())
+	if err != nil {
+		return "", err
+	}
+	filter.apply(edmx)
+	edmx.resolveNames()
+	if err = config.apply(edmx); err != nil {
+		return "", err
+	}
+
+	return generateCodeFromSchema(packageName, edmx)
+}
+
+// Generate returns the generated code without writing it to a file
+func (g Generator) Generate() (string, error) {
+	xmlData, err := g.loadMetadata()
+	if err != nil {
+		return "", err
+	}
+	return g.generateFromMetadata(xmlData)
+}
+
+// GenerateCode writes the generated code to the file in the directory, and the EDMX document to SaveMetadataFile
+func (g Generator) GenerateCode() error {
+	filePath, err := g.filePath()
 	if err != nil {
 		return err
 	}
 
-	edmx, err := fetchEdmx(g.metadataUrl())
+	xmlData, err := g.loadMetadata()
 	if err != nil {
 		return err
 	}
+	code, err := g.generateFromMetadata(xmlData)
+	if err != nil {
+		return err
+	}
+
+	if err = ioutil.WriteFile(filePath, []byte(code), 0644); err != nil {
+		return err
+	}
+	if g.SaveMetadataFile != "" {
+		return ioutil.WriteFile(filepath.Join(filepath.Dir(filePath), g.SaveMetadataFile), xmlData, 0644)
+	}
+	return nil
+}
 
-	packageName := filepath.Base(dirPath)
-	code := generateCodeFromSchema(packageName, edmx)
-	filePath := fmt.Sprintf("%s%s%s", dirPath, string(filepath.Separator), "modelDefinitions.go")
+// CheckCode returns ErrCodeOutdated when the file in the directory differs from the code which would be generated
+func (g Generator) CheckCode() error {
+	filePath, err := g.filePath()
+	if err != nil {
+		return err
+	}
 
-	file, err := os.Create(filePath)
+	code, err := g.Generate()
 	if err != nil {
 		return err
 	}
-	_, err = file.WriteString(code)
-	return err
+
+	existingCode, err := ioutil.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s: %w", filePath, ErrCodeOutdated)
+	} else if err != nil {
+		return err
+	}
+	if !bytes.Equal(existingCode, []byte(code)) {
+		return fmt.Errorf("%s: %w", filePath, ErrCodeOutdated)
+	}
+	return nil
 }
@@ -2,6 +2,13 @@ package modelGenerator
 
 import (
 	"github.com/stretchr/testify/assert"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -10,20 +17,65 @@ func Test_Generate_struct(t *testing.T) {
 	peopleSet := edmx.EntitySets["People"]
 
 	assert.Equal(t, `type Person struct {
-	AddressInfo []Location
-	Age nullable.Nullable[int64]
-	Emails []string
-	FavoriteFeature Feature
-	Features []Feature
-	FirstName string
-	Gender PersonGender
-	HomeAddress nullable.Nullable[Location]
-	LastName nullable.Nullable[string]
-	MiddleName nullable.Nullable[string]
-	UserName string
+	odataClient.EntityMetadata
+	AddressInfo []AnyLocation `+"`"+`json:"AddressInfo"`+"`"+`
+	Age nullable.Nullable[int64] `+"`"+`json:"Age"`+"`"+`
+	Emails []string `+"`"+`json:"Emails"`+"`"+`
+	FavoriteFeature Feature `+"`"+`json:"FavoriteFeature"`+"`"+`
+	Features []Feature `+"`"+`json:"Features"`+"`"+`
+	FirstName string `+"`"+`json:"FirstName"`+"`"+`
+	Gender PersonGender `+"`"+`json:"Gender"`+"`"+`
+	HomeAddress AnyLocation `+"`"+`json:"HomeAddress"`+"`"+`
+	LastName nullable.Nullable[string] `+"`"+`json:"LastName"`+"`"+`
+	MiddleName nullable.Nullable[string] `+"`"+`json:"MiddleName"`+"`"+`
+	UserName string `+"`"+`json:"UserName"`+"`"+`
+	BestFriend AnyPerson `+"`"+`json:"BestFriend,omitempty" odata:"navigation"`+"`"+`
+	Friends []AnyPerson `+"`"+`json:"Friends,omitempty" odata:"navigation"`+"`"+`
+	Trips []Trip `+"`"+`json:"Trips,omitempty" odata:"navigation"`+"`"+`
 }`, generateModelStruct(peopleSet.getEntityType()))
 }
 
+func Test_Generate_complex_type_struct(t *testing.T) {
+	edmx, _ := getParsedEdmx()
+
+	assert.Equal(t, `type City struct {
+	CountryRegion nullable.Nullable[string] `+"`"+`json:"CountryRegion"`+"`"+`
+	Name nullable.Nullable[string] `+"`"+`json:"Name"`+"`"+`
+	Region nullable.Nullable[string] `+"`"+`json:"Region"`+"`"+`
+}`, generateModelStruct(edmx.ComplexTypes["City"]))
+}
+
+func Test_Generate_derived_struct(t *testing.T) {
+	edmx, _ := getParsedEdmx()
+
+	assert.Equal(t, `type Employee struct {
+	Person
+	Cost int64 `+"`"+`json:"Cost"`+"`"+`
+	Peers []AnyPerson `+"`"+`json:"Peers,omitempty" odata:"navigation"`+"`"+`
+}`, generateModelStruct(edmx.EntityTypes["Employee"]))
+
+	assert.Equal(t, `type AirportLocation struct {
+	Location
+	Loc nullable.Nullable[geo.Point[geo.Geography]] `+"`"+`json:"Loc"`+"`"+`
+}`, generateModelStruct(edmx.ComplexTypes["AirportLocation"]))
+}
+
+func Test_Generate_polymorphic_properties(t *testing.T) {
+	edmx, _ := getParsedEdmx()
+
+	assert.True(t, edmx.EntityTypes["Person"].hasPolymorphicProperties())
+	assert.True(t, edmx.EntityTypes["Employee"].hasPolymorphicProperties())
+	assert.False(t, edmx.EntityTypes["Airline"].hasPolymorphicProperties())
+	assert.False(t, edmx.ComplexTypes["AirportLocation"].hasPolymorphicProperties())
+	assert.True(t, edmx.EntityTypes["Trip"].hasPolymorphicProperties())
+	assert.Equal(t, "[]AnyPlanItem", edmx.EntityTypes["Trip"].NavigationProperties["PlanItems"].goType())
+	assert.Equal(t, "*Airline", edmx.EntityTypes["Flight"].NavigationProperties["Airline"].goType())
+
+	assert.Equal(t, `func (m *Employee) UnmarshalJSON(data []byte) error {
+	return odataClient.UnmarshalModel(data, m)
+}`, generateModelUnmarshaler(edmx.EntityTypes["Employee"]))
+}
+
 func Test_Generate_definition(t *testing.T) {
 	edmx, _ := getParsedEdmx()
 	peopleSet := edmx.EntitySets["People"]
@@ -34,14 +86,523 @@ func NewPersonCollection(wrapper odataClient.Wrapper) odataClient.ODataModelColl
 }`, generateModelDefinition(peopleSet))
 }
 
+func Test_Generate_derived_definition(t *testing.T) {
+	ds, _ := getParsedMultiSchemaEdmx()
+	peopleSet := ds.Schemas["Trippin.Data"].EntitySets["People"]
+
+	derivedTypes := ds.derivedTypes(peopleSet.getEntityType())
+	assert.Len(t, derivedTypes, 2)
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func NewEmployeeCollection(wrapper odataClient.Wrapper) odataClient.ODataModelCollection[Employee] {
+	return modelDefinition[Employee]{client: wrapper.ODataClient(), name: "Employee", url: "People/Trippin.Model.Employee"}
+}`, generateDerivedModelDefinition(peopleSet, derivedTypes[0]))
+}
+
+func Test_Generate_polymorphic_interface(t *testing.T) {
+	edmx, _ := getParsedEdmx()
+
+	assert.True(t, edmx.EntityTypes["Person"].isPolymorphic())
+	assert.False(t, edmx.EntityTypes["Employee"].isPolymorphic())
+	assert.True(t, edmx.ComplexTypes["Location"].isPolymorphic())
+	assert.False(t, edmx.ComplexTypes["City"].isPolymorphic())
+
+	assert.Equal(t, `// AnyPerson is implemented by Person and the types derived from it
+type AnyPerson interface {
+	AsPerson() Person
+}
+
+func (m Person) AsPerson() Person {
+	return m
+}`, generatePolymorphicInterface(edmx.EntityTypes["Person"]))
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func NewAnyPersonCollection(wrapper odataClient.Wrapper) odataClient.ODataModelCollection[AnyPerson] {
+	return modelDefinition[AnyPerson]{client: wrapper.ODataClient(), name: "AnyPerson", url: "People"}
+}`, generatePolymorphicModelDefinition(edmx.EntitySets["People"]))
+}
+
+func Test_Generate_type_registry(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<ComplexType Name="Address"/>
+<EntityType Name="Document" Abstract="true">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+</EntityType>
+<EntityType Name="Invoice" BaseType="Shop.Document"/>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, `func init() {
+	odataClient.RegisterType[Address]("Shop.Address")
+	odataClient.RegisterType[Document]("Shop.Document")
+	odataClient.RegisterFallbackType[AnyDocument, Document]()
+	odataClient.RegisterType[Invoice]("Shop.Invoice")
+}`, generateTypeRegistry(ds))
+}
+
+func Test_Generate_singleton_definition(t *testing.T) {
+	edmx, _ := getParsedEdmx()
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func NewMeSingleton(wrapper odataClient.Wrapper) odataClient.SingletonSet[Person] {
+	return odataClient.NewSingletonSet[Person](wrapper.ODataClient(), "Me")
+}`, generateSingletonDefinition(edmx.Singletons["Me"]))
+}
+
 func Test_Generate_enum(t *testing.T) {
 	edmx, _ := getParsedEdmx()
 	genderEnum := edmx.EnumTypes["PersonGender"]
-	assert.Equal(t, `type PersonGender int64
+	assert.Equal(t, `type PersonGender int32
 
 const (
-	Male PersonGender = 0
-	Female PersonGender = 1
-	Unknown PersonGender = 2
-)`, generateEnumStruct(genderEnum))
+	PersonGenderMale PersonGender = 0
+	PersonGenderFemale PersonGender = 1
+	PersonGenderUnknown PersonGender = 2
+)
+
+var personGenderEnum = odataClient.NewEnumType[PersonGender]("Trippin.PersonGender", false,
+	odataClient.EnumMember[PersonGender]{Value: PersonGenderMale, Name: "Male"},
+	odataClient.EnumMember[PersonGender]{Value: PersonGenderFemale, Name: "Female"},
+	odataClient.EnumMember[PersonGender]{Value: PersonGenderUnknown, Name: "Unknown"},
+)
+
+// PersonGenderValues returns all members of PersonGender in the order of their values
+//goland:noinspection GoUnusedExportedFunction
+func PersonGenderValues() []PersonGender {
+	return personGenderEnum.Values()
+}
+
+// ParsePersonGender returns the value of the member with the name in the service
+//goland:noinspection GoUnusedExportedFunction
+func ParsePersonGender(name string) (PersonGender, error) {
+	return personGenderEnum.Parse(name)
+}
+
+func (e PersonGender) String() string {
+	return personGenderEnum.Format(e)
+}
+
+func (e PersonGender) ODataLiteral() string {
+	return personGenderEnum.Literal(e)
+}
+
+func (e PersonGender) MarshalJSON() ([]byte, error) {
+	return personGenderEnum.EncodeJSON(e)
+}
+
+func (e *PersonGender) UnmarshalJSON(data []byte) error {
+	return personGenderEnum.DecodeJSON(data, e)
+}`, generateEnumStruct(genderEnum))
+}
+
+func Test_Generate_key(t *testing.T) {
+	edmx, _ := getParsedEdmx()
+
+	assert.Equal(t, []string{"UserName"}, edmx.EntityTypes["Person"].Key)
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func PersonKey(userName string) odataClient.EntityKey {
+	return odataClient.Key(userName)
+}`, generateKeyFunction(edmx.EntityTypes["Person"]))
+}
+
+func Test_Generate_composite_key(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EntityType Name="OrderDetail">
+<Key>
+<PropertyRef Name="OrderID"/>
+<PropertyRef Name="Type"/>
+</Key>
+<Property Name="OrderID" Type="Edm.Int32" Nullable="false"/>
+<Property Name="Type" Type="Edm.String" Nullable="false"/>
+</EntityType>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func OrderDetailKey(orderID int32, typeValue string) odataClient.EntityKey {
+	return odataClient.CompositeKey(
+		odataClient.KeyProperty{Name: "OrderID", Value: orderID},
+		odataClient.KeyProperty{Name: "Type", Value: typeValue},
+	)
+}`, generateKeyFunction(ds.Schemas["Shop"].EntityTypes["OrderDetail"]))
+}
+
+func Test_goParameterName(t *testing.T) {
+	assert.Equal(t, "userName", goParameterName("UserName"))
+	assert.Equal(t, "id", goParameterName("ID"))
+	assert.Equal(t, "orderID", goParameterName("OrderID"))
+	assert.Equal(t, "urlPath", goParameterName("URLPath"))
+	assert.Equal(t, "rangeValue", goParameterName("range"))
+}
+
+func getOperation(edmx edmxSchema, name string) edmxOperation {
+	for _, operation := range edmx.Operations {
+		if operation.Name == name {
+			return operation
+		}
+	}
+	return edmxOperation{}
+}
+
+func Test_Generate_bound_operations(t *testing.T) {
+	edmx, _ := getParsedEdmx()
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func PersonGetFriendsTrips(ctx context.Context, entity odataClient.ODataEntity[Person], userName string) ([]Trip, error) {
+	return odataClient.CallFunction[[]Trip](ctx, entity, "Trippin.GetFriendsTrips", map[string]interface{}{"userName": userName})
+}`, generateBoundOperation(getOperation(edmx, "GetFriendsTrips")))
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func PersonShareTrip(ctx context.Context, entity odataClient.ODataEntity[Person], userName string, tripId int32) error {
+	_, err := odataClient.CallAction[struct{}](ctx, entity, "Trippin.ShareTrip", map[string]interface{}{"userName": userName, "tripId": tripId})
+	return err
+}`, generateBoundOperation(getOperation(edmx, "ShareTrip")))
+
+	assert.Equal(t, "", generateBoundOperation(getOperation(edmx, "GetNearestAirport")))
+}
+
+func Test_Generate_collection_bound_operation(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EntityType Name="Order">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+</EntityType>
+<Function Name="Total" IsBound="true">
+<Parameter Name="orders" Type="Collection(Shop.Order)"/>
+<Parameter Name="dataSet" Type="Edm.String"/>
+<ReturnType Type="Edm.Double"/>
+</Function>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func OrderCollectionTotal(ctx context.Context, dataSet odataClient.ODataDataSet[Order, odataClient.ODataModelDefinition[Order]], dataSetValue nullable.Nullable[string]) (float64, error) {
+	return odataClient.CallFunction[float64](ctx, dataSet, "Shop.Total", map[string]interface{}{"dataSet": dataSetValue})
+}`, generateBoundOperation(ds.Schemas["Shop"].Operations[0]))
+}
+
+func Test_Generate_overloaded_bound_operations(t *testing.T) {
+	code, err := Generator{PackageName: "shop", Metadata: []byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EntityType Name="Order">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+</EntityType>
+<EntityType Name="Customer">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+</EntityType>
+<Function Name="Total" IsBound="true">
+<Parameter Name="order" Type="Shop.Order"/>
+<ReturnType Type="Edm.Double"/>
+</Function>
+<Function Name="Total" IsBound="true">
+<Parameter Name="order" Type="Shop.Order"/>
+<Parameter Name="currency" Type="Edm.String" Nullable="false"/>
+<ReturnType Type="Edm.Double"/>
+</Function>
+<Function Name="Total" IsBound="true">
+<Parameter Name="order" Type="Shop.Order"/>
+<Parameter Name="currency" Type="Edm.Int32" Nullable="false"/>
+<ReturnType Type="Edm.Double"/>
+</Function>
+<Function Name="Total" IsBound="true">
+<Parameter Name="customer" Type="Shop.Customer"/>
+<ReturnType Type="Edm.Double"/>
+</Function>
+<EntityContainer Name="Container">
+<EntitySet Name="Orders" EntityType="Shop.Order"/>
+<EntitySet Name="Customers" EntityType="Shop.Customer"/>
+</EntityContainer>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`)}.Generate()
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "func OrderTotal(ctx context.Context, entity odataClient.ODataEntity[Order]) (float64, error) {")
+	assert.Contains(t, code, "func OrderTotalCurrency(ctx context.Context, entity odataClient.ODataEntity[Order], currency string) (float64, error) {")
+	assert.Contains(t, code, "func OrderTotalCurrency2(ctx context.Context, entity odataClient.ODataEntity[Order], currency int32) (float64, error) {")
+	assert.Contains(t, code, "func CustomerTotal(ctx context.Context, entity odataClient.ODataEntity[Customer]) (float64, error) {")
+	assertUniqueDeclarations(t, code)
+}
+
+func Test_Generate_overloaded_operation_imports(t *testing.T) {
+	code, err := Generator{PackageName: "shop", Metadata: []byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<Function Name="Total">
+<ReturnType Type="Edm.Double"/>
+</Function>
+<Function Name="Total">
+<Parameter Name="currency" Type="Edm.String" Nullable="false"/>
+<ReturnType Type="Edm.Double"/>
+</Function>
+<Function Name="Total">
+<Parameter Name="from" Type="Edm.Date" Nullable="false"/>
+<Parameter Name="to" Type="Edm.Date" Nullable="false"/>
+<ReturnType Type="Edm.Double"/>
+</Function>
+<EntityContainer Name="Container">
+<FunctionImport Name="GetTotal" Function="Shop.Total"/>
+</EntityContainer>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`)}.Generate()
+	assert.NoError(t, err)
+
+	assert.Contains(t, code, "func GetTotal(ctx context.Context, wrapper odataClient.Wrapper) (float64, error) {")
+	assert.Contains(t, code, "func GetTotalCurrency(ctx context.Context, wrapper odataClient.Wrapper, currency string) (float64, error) {")
+	assert.Contains(t, code, "func GetTotalFromTo(ctx context.Context, wrapper odataClient.Wrapper, from date.Date, to date.Date) (float64, error) {")
+	assertUniqueDeclarations(t, code)
+}
+
+// assertUniqueDeclarations parses the generated code and checks that no package level name is declared twice
+func assertUniqueDeclarations(t *testing.T, code string) {
+	file, err := parser.ParseFile(token.NewFileSet(), "generated.go", code, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	declared := map[string]bool{}
+	declare := func(name string) {
+		assert.False(t, declared[name], "%s is declared twice", name)
+		declared[name] = true
+	}
+	for _, declaration := range file.Decls {
+		switch declaration := declaration.(type) {
+		case *ast.FuncDecl:
+			if declaration.Recv == nil && declaration.Name.Name != "init" {
+				declare(declaration.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range declaration.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					declare(spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						declare(name.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func Test_Generate_operation_imports(t *testing.T) {
+	edmx, _ := getParsedEdmx()
+
+	generated := map[string]string{}
+	for _, operationImport := range edmx.OperationImports {
+		generated[operationImport.Name] = generateOperationImport(operationImport)
+	}
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func GetNearestAirport(ctx context.Context, wrapper odataClient.Wrapper, lat float64, lon float64) (Airport, error) {
+	return odataClient.CallFunction[Airport](ctx, odataClient.ServiceRoot(wrapper.ODataClient()), "GetNearestAirport", map[string]interface{}{"lat": lat, "lon": lon})
+}`, generated["GetNearestAirport"])
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func ResetDataSource(ctx context.Context, wrapper odataClient.Wrapper) error {
+	_, err := odataClient.CallAction[struct{}](ctx, odataClient.ServiceRoot(wrapper.ODataClient()), "ResetDataSource", nil)
+	return err
+}`, generated["ResetDataSource"])
+}
+
+func Test_Generate_code_is_deterministic(t *testing.T) {
+	ds, _ := getParsedMultiSchemaEdmx()
+
+	code, err := generateCodeFromSchema("trippin", ds)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		regenerated, err := generateCodeFromSchema("trippin", ds)
+		assert.NoError(t, err)
+		assert.Equal(t, code, regenerated)
+	}
+
+	formatted, err := format.Source([]byte(code))
+	assert.NoError(t, err)
+	assert.Equal(t, code, string(formatted))
+}
+
+func Test_Generate_code_imports(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EntityType Name="Order">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+<Property Name="Delivery" Type="Edm.Date" Nullable="false"/>
+</EntityType>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+
+	code, err := generateCodeFromSchema("shop", ds)
+	assert.NoError(t, err)
+	assert.Contains(t, code, `import (
+	"github.com/Uffe-Code/go-odata/date"
+	"github.com/Uffe-Code/go-odata/odataClient"
+)`)
+	assert.Contains(t, code, "\tDelivery date.Date `json:\"Delivery\"`\n")
+}
+
+func Test_Generator_metadata_sources(t *testing.T) {
+	directory := t.TempDir()
+	metadataFile := filepath.Join(directory, "trippin.xml")
+	assert.NoError(t, os.WriteFile(metadataFile, []byte(trippinEdmxSchema), 0644))
+
+	expected, err := Generator{Metadata: []byte(trippinEdmxSchema), PackageName: "trippin"}.Generate()
+	assert.NoError(t, err)
+	assert.Contains(t, expected, "package trippin\n")
+
+	fromReader, err := Generator{MetadataReader: strings.NewReader(trippinEdmxSchema), PackageName: "trippin"}.Generate()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, fromReader)
+
+	fromFile, err := Generator{MetadataFile: metadataFile, PackageName: "trippin"}.Generate()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, fromFile)
+
+	_, err = Generator{}.Generate()
+	assert.Error(t, err)
+}
+
+func Test_Generator_save_metadata(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "trippin")
+	assert.NoError(t, os.Mkdir(directory, 0755))
+
+	generator := Generator{
+		MetadataReader:   strings.NewReader(trippinEdmxSchema),
+		DirectoryPath:    directory,
+		SaveMetadataFile: "metadata.xml",
+	}
+	assert.NoError(t, generator.GenerateCode())
+
+	savedMetadata, err := os.ReadFile(filepath.Join(directory, "metadata.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, trippinEdmxSchema, string(savedMetadata))
+
+	offline := Generator{MetadataFile: filepath.Join(directory, "metadata.xml"), DirectoryPath: directory}
+	assert.NoError(t, offline.CheckCode())
+}
+
+func Test_Generate_primitive_types(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<ComplexType Name="Primitives">
+<Property Name="Binary" Type="Edm.Binary" Nullable="false"/>
+<Property Name="Decimal" Type="Edm.Decimal" Nullable="false" Precision="18" Scale="4"/>
+<Property Name="Double" Type="Edm.Double" Nullable="false"/>
+<Property Name="Duration" Type="Edm.Duration" Nullable="false"/>
+<Property Name="Guid" Type="Edm.Guid"/>
+<Property Name="Prices" Type="Collection(Edm.Decimal)" Scale="variable"/>
+<Property Name="Single" Type="Edm.Single" Nullable="false"/>
+<Property Name="Stream" Type="Edm.Stream"/>
+<Property Name="TimeOfDay" Type="Edm.TimeOfDay" Nullable="false"/>
+</ComplexType>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "type Primitives struct {"+
+		"\n\tBinary []byte `json:\"Binary\"`"+
+		"\n\tDecimal decimal.Decimal `json:\"Decimal\" odata:\"precision=18,scale=4\"`"+
+		"\n\tDouble float64 `json:\"Double\"`"+
+		"\n\tDuration date.Duration `json:\"Duration\"`"+
+		"\n\tGuid nullable.Nullable[uuid.UUID] `json:\"Guid\"`"+
+		"\n\tPrices []decimal.Decimal `json:\"Prices\" odata:\"scale=variable\"`"+
+		"\n\tSingle float32 `json:\"Single\"`"+
+		"\n\tStream nullable.Nullable[[]byte] `json:\"Stream\"`"+
+		"\n\tTimeOfDay date.TimeOfDay `json:\"TimeOfDay\"`"+
+		"\n}", generateModelStruct(ds.Schemas["Shop"].ComplexTypes["Primitives"]))
+
+	code, err := generateCodeFromSchema("shop", ds)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "\t\"github.com/Uffe-Code/go-odata/decimal\"\n")
+	assert.Contains(t, code, "\t\"github.com/Uffe-Code/go-odata/uuid\"\n")
+}
+
+func Test_geoType(t *testing.T) {
+	assert.Equal(t, "geo.Point[geo.Geography]", geoType("Edm.GeographyPoint"))
+	assert.Equal(t, "geo.MultiPolygon[geo.Geometry]", geoType("Edm.GeometryMultiPolygon"))
+	assert.Equal(t, "geo.Collection[geo.Geography]", geoType("Edm.GeographyCollection"))
+	assert.Equal(t, "geo.Any[geo.Geometry]", geoType("Edm.Geometry"))
+	assert.Equal(t, "", geoType("Edm.GeographyCircle"))
+	assert.Equal(t, "", geoType("Edm.String"))
+}
+
+func Test_Generate_flags_enum(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EnumType Name="Permission" UnderlyingType="Edm.Byte" IsFlags="true">
+<Member Name="Write" Value="2"/>
+<Member Name="None" Value="0"/>
+<Member Name="Read" Value="1"/>
+<Member Name="ReadWrite" Value="3"/>
+</EnumType>
+<EnumType Name="Size" UnderlyingType="Edm.Int64">
+<Member Name="None"/>
+<Member Name="Small"/>
+<Member Name="Large"/>
+<Member Name="Values"/>
+</EnumType>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+	schema := ds.Schemas["Shop"]
+
+	permission := generateEnumStruct(schema.EnumTypes["Permission"])
+	assert.Contains(t, permission, `type Permission uint8
+
+const (
+	PermissionNone Permission = 0
+	PermissionRead Permission = 1
+	PermissionWrite Permission = 2
+	PermissionReadWrite Permission = 3
+)
+
+var permissionEnum = odataClient.NewEnumType[Permission]("Shop.Permission", true,`)
+	assert.Contains(t, permission, `
+func (e Permission) Has(flags Permission) bool {
+	return e&flags == flags
+}`)
+	assert.Contains(t, permission, "func (e Permission) With(flags Permission) Permission {")
+	assert.Contains(t, permission, "func (e Permission) Without(flags Permission) Permission {")
+
+	size := generateEnumStruct(schema.EnumTypes["Size"])
+	assert.Contains(t, size, "type Size int64\n\nconst (\n\tSizeNone Size = 0\n\tSizeSmall Size = 1\n\tSizeLarge Size = 2\n\tSizeValues2 Size = 3\n)")
+	assert.Contains(t, size, `odataClient.NewEnumType[Size]("Shop.Size", false,`)
+	assert.NotContains(t, size, "Has(")
+
+	code, err := generateCodeFromSchema("shop", ds)
+	assert.NoError(t, err)
+	assertUniqueDeclarations(t, code)
 }
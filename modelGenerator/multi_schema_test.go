@@ -215,3 +215,102 @@ func Test_Parse_multi_schema_edmx(t *testing.T) {
 	assert.Equal(t, "Trippin.Model.Person", peopleEntitySet.EntityType)
 	assert.Equal(t, personEntityType, peopleEntitySet.getEntityType())
 }
+
+func Test_Parse_base_type_across_schemas(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Base">
+<EntityType Name="Document" Abstract="true">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+</EntityType>
+</Schema>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Sales">
+<EntityType Name="Invoice" BaseType="Shop.Base.Document">
+<Property Name="Total" Type="Edm.Double" Nullable="false"/>
+</EntityType>
+<EntityType Name="CreditNote" BaseType="Shop.Sales.Invoice"/>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+
+	document := ds.Schemas["Shop.Base"].EntityTypes["Document"]
+	assert.True(t, document.Abstract)
+	creditNote := ds.Schemas["Shop.Sales"].EntityTypes["CreditNote"]
+	assert.False(t, creditNote.Abstract)
+
+	baseType, ok := creditNote.getBaseType()
+	assert.True(t, ok)
+	assert.Equal(t, "Shop.Sales.Invoice", baseType.qualifiedName())
+	assert.True(t, creditNote.isDerivedFrom(document))
+	assert.False(t, document.isDerivedFrom(creditNote))
+
+	derivedTypes := ds.derivedTypes(document)
+	assert.Equal(t, []string{"CreditNote", "Invoice"}, []string{derivedTypes[0].Name, derivedTypes[1].Name})
+	assert.Equal(t, "type CreditNote struct {\n\tInvoice\n}", generateModelStruct(creditNote))
+	assert.Equal(t, "type Invoice struct {\n\tDocument\n\tTotal float64 `json:\"Total\"`\n}", generateModelStruct(ds.Schemas["Shop.Sales"].EntityTypes["Invoice"]))
+}
+
+func Test_Parse_schema_alias(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Common.V1" Alias="Common">
+<ComplexType Name="Location">
+<Property Name="City" Type="Edm.String"/>
+</ComplexType>
+<EnumType Name="Status">
+<Member Name="Active" Value="0"/>
+</EnumType>
+</Schema>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop.Sales.V1" Alias="Sales">
+<EntityType Name="Customer">
+<Key>
+<PropertyRef Name="ID"/>
+</Key>
+<Property Name="ID" Type="Edm.Int32" Nullable="false"/>
+<Property Name="Location" Type="Common.Location"/>
+<Property Name="Locations" Type="Collection(Shop.Common.V1.Location)"/>
+<Property Name="Status" Type="Common.Status" Nullable="false"/>
+</EntityType>
+<ComplexType Name="Location">
+<Property Name="Region" Type="Edm.String"/>
+</ComplexType>
+<Function Name="CustomerCount">
+<ReturnType Type="Edm.Int32"/>
+</Function>
+<EntityContainer Name="Container">
+<EntitySet Name="Customers" EntityType="Sales.Customer"/>
+<FunctionImport Name="CustomerCount" Function="Sales.CustomerCount"/>
+</EntityContainer>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+
+	schema, ok := ds.lookupSchema("Common")
+	assert.True(t, ok)
+	assert.Equal(t, "Shop.Common.V1", schema.Namespace)
+	assert.Equal(t, "Common", schema.Alias)
+
+	sales := ds.Schemas["Shop.Sales.V1"]
+	customer := sales.EntitySets["Customers"].getEntityType()
+	assert.Equal(t, "Customer", customer.Name)
+	assert.Equal(t, "nullable.Nullable[CommonV1Location]", customer.Properties["Location"].goType())
+	assert.Equal(t, "[]CommonV1Location", customer.Properties["Locations"].goType())
+	assert.Equal(t, "Status", customer.Properties["Status"].goType())
+	assert.Equal(t, "SalesV1Location", sales.ComplexTypes["Location"].goName())
+
+	operations := sales.OperationImports[0].getOperations()
+	if assert.Len(t, operations, 1) {
+		assert.Equal(t, "Shop.Sales.V1.CustomerCount", operations[0].qualifiedName())
+	}
+
+	code, err := generateCodeFromSchema("shop", ds)
+	assert.NoError(t, err)
+	assert.Contains(t, code, "type CommonV1Location struct {")
+	assert.Contains(t, code, "type SalesV1Location struct {")
+	assert.Contains(t, code, `odataClient.RegisterType[CommonV1Location]("Shop.Common.V1.Location")`)
+}
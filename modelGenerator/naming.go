@@ -0,0 +1,197 @@
+package modelGenerator
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// goIdentifier converts an EDMX name to an exported Go identifier. Characters which are not valid in identifiers,
+// like underscores, separate words, and each word starts with an upper case letter, e.g. first_name to FirstName.
+func goIdentifier(name string) string {
+	var identifier strings.Builder
+	startOfWord := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			startOfWord = true
+			continue
+		}
+		if startOfWord {
+			r = unicode.ToUpper(r)
+			startOfWord = false
+		}
+		identifier.WriteRune(r)
+	}
+
+	goName := identifier.String()
+	if goName == "" {
+		return "X"
+	}
+	if first := []rune(goName)[0]; !unicode.IsUpper(first) {
+		goName = "X" + goName
+	}
+	return goName
+}
+
+// uniqueNames maps each name to a Go identifier, numbering identifiers which are already taken,
+// e.g. when the names only differ in case
+func uniqueNames(names []string, reserved ...string) map[string]string {
+	taken := map[string]bool{}
+	for _, name := range reserved {
+		taken[name] = true
+	}
+	goNames := map[string]string{}
+	for _, name := range names {
+		goName := goIdentifier(name)
+		for i := 2; taken[goName]; i++ {
+			goName = goIdentifier(name) + strconv.Itoa(i)
+		}
+		taken[goName] = true
+		goNames[name] = goName
+	}
+	return goNames
+}
+
+// fieldNames maps the property names of the type to the names of the struct fields. Names set by the Config
+// are kept as they are, the other names are converted and numbered when they clash.
+func (e edmxEntityType) fieldNames(names []string, reserved ...string) map[string]string {
+	config := e.schema.dataService.config
+	configured := map[string]string{}
+	var converted []string
+	for _, name := range names {
+		if fieldName := config.fieldName(e.qualifiedName(), name); fieldName != "" {
+			configured[name] = fieldName
+			reserved = append(reserved, fieldName)
+		} else {
+			converted = append(converted, name)
+		}
+	}
+	fieldNames := uniqueNames(converted, reserved...)
+	for name, fieldName := range configured {
+		fieldNames[name] = fieldName
+	}
+	return fieldNames
+}
+
+// resolveNames names the Go types and the overloaded operations. It is called again after the filter has removed
+// parts of the data service, so names only need to be told apart from the names of the generated parts.
+func (ds edmxDataServices) resolveNames() {
+	for qualifiedName := range ds.goNames {
+		delete(ds.goNames, qualifiedName)
+	}
+	for qualifiedName, goName := range qualifiedGoNames(ds.qualifiedTypeNames()) {
+		ds.goNames[qualifiedName] = goName
+	}
+	ds.nameOverloads()
+}
+
+// nameOverloads sets the suffixes of the functions generated for bound operations with the same name and binding
+// type, and for unbound operations with the same qualified name, which are generated by the name of their import.
+// Each overload is suffixed with the names of its parameters, e.g. OrderTotal and OrderTotalCurrency,
+// and numbered when the parameter names are the same as well.
+func (ds edmxDataServices) nameOverloads() {
+	overloads := map[string][]*edmxOperation{}
+	var bindings []string
+	for _, namespace := range sortedKeys(ds.Schemas) {
+		operations := ds.Schemas[namespace].Operations
+		for i := range operations {
+			binding := operations[i].qualifiedName()
+			if bindingParameter, ok := operations[i].bindingParameter(); ok {
+				bindingType := ds.normalizeQualifiedName(trimCollection(bindingParameter.Type))
+				binding = goIdentifier(operations[i].Name) + " " + strings.Replace(bindingParameter.Type, trimCollection(bindingParameter.Type), bindingType, 1)
+			} else if operations[i].IsBound {
+				continue
+			}
+			if len(overloads[binding]) == 0 {
+				bindings = append(bindings, binding)
+			}
+			overloads[binding] = append(overloads[binding], &operations[i])
+		}
+	}
+
+	for _, binding := range bindings {
+		if len(overloads[binding]) < 2 {
+			overloads[binding][0].overloadSuffix = ""
+			continue
+		}
+		taken := map[string]bool{}
+		suffixes := map[string]string{}
+		for _, operation := range overloads[binding] {
+			if suffix, ok := suffixes[operation.signature()]; ok {
+				operation.overloadSuffix = suffix
+				continue
+			}
+			parameterNames := ""
+			for _, parameter := range operation.callParameters() {
+				parameterNames += goIdentifier(parameter.Name)
+			}
+			suffix := parameterNames
+			for i := 2; taken[suffix]; i++ {
+				suffix = parameterNames + strconv.Itoa(i)
+			}
+			taken[suffix] = true
+			suffixes[operation.signature()] = suffix
+			operation.overloadSuffix = suffix
+		}
+	}
+}
+
+// qualifiedGoNames maps namespace qualified type names to Go identifiers. Types with the same name in different
+// namespaces are prefixed with as many segments of their namespace as needed to tell them apart,
+// e.g. Shop.Sales.Address and Shop.Billing.Address to SalesAddress and BillingAddress.
+func qualifiedGoNames(qualifiedNames []string) map[string]string {
+	qualifiedNames = append([]string{}, qualifiedNames...)
+	sort.Strings(qualifiedNames)
+	byName := map[string][]string{}
+	for _, qualifiedName := range qualifiedNames {
+		_, name := splitQualifiedName(qualifiedName)
+		byName[goIdentifier(name)] = append(byName[goIdentifier(name)], qualifiedName)
+	}
+
+	candidates := map[string]string{}
+	for goName, clashing := range byName {
+		namespaces := map[string]bool{}
+		for _, qualifiedName := range clashing {
+			namespace, _ := splitQualifiedName(qualifiedName)
+			namespaces[namespace] = true
+		}
+		if len(namespaces) == 1 {
+			for _, qualifiedName := range clashing {
+				candidates[qualifiedName] = goName
+			}
+			continue
+		}
+		for segments := 1; ; segments++ {
+			taken := map[string]bool{}
+			unique, exhausted := true, true
+			for _, qualifiedName := range clashing {
+				namespace, name := splitQualifiedName(qualifiedName)
+				parts := strings.Split(namespace, ".")
+				if segments < len(parts) {
+					parts = parts[len(parts)-segments:]
+					exhausted = false
+				}
+				candidate := goIdentifier(strings.Join(append(parts, name), "."))
+				unique = unique && !taken[candidate]
+				taken[candidate] = true
+				candidates[qualifiedName] = candidate
+			}
+			if unique || exhausted {
+				break
+			}
+		}
+	}
+
+	taken := map[string]bool{}
+	goNames := map[string]string{}
+	for _, qualifiedName := range qualifiedNames {
+		goName := candidates[qualifiedName]
+		for i := 2; taken[goName]; i++ {
+			goName = candidates[qualifiedName] + strconv.Itoa(i)
+		}
+		taken[goName] = true
+		goNames[qualifiedName] = goName
+	}
+	return goNames
+}
@@ -0,0 +1,110 @@
+package modelGenerator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_goIdentifier(t *testing.T) {
+	assert.Equal(t, "UserName", goIdentifier("UserName"))
+	assert.Equal(t, "Id", goIdentifier("id"))
+	assert.Equal(t, "ID", goIdentifier("ID"))
+	assert.Equal(t, "FirstName", goIdentifier("first_name"))
+	assert.Equal(t, "OrderLineNo", goIdentifier("Order-Line.No"))
+	assert.Equal(t, "Type", goIdentifier("type"))
+	assert.Equal(t, "X2ndAddress", goIdentifier("2ndAddress"))
+	assert.Equal(t, "Ærø", goIdentifier("ærø"))
+	assert.Equal(t, "X", goIdentifier("_"))
+}
+
+func Test_uniqueNames(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"Name":           "Name",
+		"name":           "Name2",
+		"entityMetadata": "EntityMetadata2",
+	}, uniqueNames([]string{"Name", "name", "entityMetadata"}, "EntityMetadata"))
+}
+
+func Test_qualifiedGoNames(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"Shop.Sales.Address":   "SalesAddress",
+		"Shop.Billing.Address": "BillingAddress",
+		"Shop.Sales.Order":     "Order",
+		"A.Common.Item":        "ACommonItem",
+		"B.Common.Item":        "BCommonItem",
+		"Shop.Status":          "Status",
+		"Shop.status":          "Status2",
+	}, qualifiedGoNames([]string{"Shop.Sales.Address", "Shop.Billing.Address", "Shop.Sales.Order", "A.Common.Item", "B.Common.Item", "Shop.Status", "Shop.status"}))
+}
+
+func Test_Generate_sanitized_names(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EnumType Name="order_status">
+<Member Name="open" Value="0"/>
+<Member Name="in_progress" Value="1"/>
+</EnumType>
+<EntityType Name="order_line">
+<Key>
+<PropertyRef Name="id"/>
+</Key>
+<Property Name="id" Type="Edm.Int32" Nullable="false"/>
+<Property Name="type" Type="Edm.String" Nullable="false"/>
+<Property Name="Type" Type="Edm.String" Nullable="false"/>
+<Property Name="status" Type="Shop.order_status" Nullable="false"/>
+</EntityType>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+	schema := ds.Schemas["Shop"]
+
+	assert.Equal(t, "type OrderLine struct {\n\todataClient.EntityMetadata\n"+
+		"\tType string `json:\"Type\"`\n"+
+		"\tId int32 `json:\"id\"`\n"+
+		"\tStatus OrderStatus `json:\"status\"`\n"+
+		"\tType2 string `json:\"type\"`\n}", generateModelStruct(schema.EntityTypes["order_line"]))
+
+	assert.Equal(t, `//goland:noinspection GoUnusedExportedFunction
+func OrderLineKey(id int32) odataClient.EntityKey {
+	return odataClient.Key(id)
+}`, generateKeyFunction(schema.EntityTypes["order_line"]))
+
+	assert.Contains(t, generateEnumStruct(schema.EnumTypes["order_status"]), `type OrderStatus int32
+
+const (
+	OrderStatusOpen OrderStatus = 0
+	OrderStatusInProgress OrderStatus = 1
+)
+
+var orderStatusEnum = odataClient.NewEnumType[OrderStatus]("Shop.order_status", false,
+	odataClient.EnumMember[OrderStatus]{Value: OrderStatusOpen, Name: "open"},
+	odataClient.EnumMember[OrderStatus]{Value: OrderStatusInProgress, Name: "in_progress"},
+)`)
+}
+
+func Test_Generate_enum_members_with_the_same_name(t *testing.T) {
+	ds, err := parseEdmx([]byte(`<edmx:Edmx xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx" Version="4.0">
+<edmx:DataServices>
+<Schema xmlns="http://docs.oasis-open.org/odata/ns/edm" Namespace="Shop">
+<EnumType Name="Color">
+<Member Name="None"/>
+<Member Name="Red"/>
+</EnumType>
+<EnumType Name="Size">
+<Member Name="None"/>
+<Member Name="Color"/>
+</EnumType>
+</Schema>
+</edmx:DataServices>
+</edmx:Edmx>`))
+	assert.NoError(t, err)
+
+	code, err := generateCodeFromSchema("shop", ds)
+	assert.NoError(t, err)
+	assert.Regexp(t, "ColorNone +Color = 0", code)
+	assert.Regexp(t, "SizeNone +Size = 0", code)
+	assert.Regexp(t, "SizeColor +Size = 1", code)
+	assertUniqueDeclarations(t, code)
+}
@@ -209,4 +209,9 @@ func Test_Parse_edmx(t *testing.T) {
 	peopleEntitySet := edmx.EntitySets["People"]
 	assert.Equal(t, "Trippin.Person", peopleEntitySet.EntityType)
 	assert.Equal(t, personEntityType, peopleEntitySet.getEntityType())
+
+	meSingleton, ok := edmx.Singletons["Me"]
+	assert.True(t, ok)
+	assert.Equal(t, "Trippin.Person", meSingleton.EntityType)
+	assert.Equal(t, personEntityType, meSingleton.getEntityType())
 }
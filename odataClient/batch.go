@@ -0,0 +1,519 @@
+package odataClient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BatchFormat is the format used to serialize a $batch request
+type BatchFormat int
+
+const (
+	// BatchFormatMultipart serializes the batch as multipart/mixed, which is supported by all OData v4 services
+	BatchFormatMultipart BatchFormat = iota
+	// BatchFormatJSON serializes the batch as JSON, which is supported by OData v4.01 services
+	BatchFormatJSON
+)
+
+// BatchQueue is either a Batch or a ChangeSet, which operations can be added to
+type BatchQueue interface {
+	add(operation *batchOperation)
+	getBatch() *Batch
+}
+
+// Batch collects operations from any data set, which are sent to the service in a single $batch request
+type Batch struct {
+	client   *oDataClient
+	format   BatchFormat
+	items    []batchItem
+	lastId   int
+	executed bool
+}
+
+// ChangeSet is a group of operations in a Batch, which the service applies atomically. Operations in
+// the change set can reference entities created earlier in the same change set with BatchResult.Entity.
+type ChangeSet struct {
+	batch      *Batch
+	id         string
+	operations []*batchOperation
+}
+
+// batchItem is either a single operation or a change set, in the order they were added to the batch
+type batchItem struct {
+	operation *batchOperation
+	changeSet *ChangeSet
+}
+
+type batchOperation struct {
+	contentId      string
+	request        *http.Request
+	body           []byte
+	atomicityGroup string
+	handle         func(response *http.Response, body []byte, err error)
+}
+
+// BatchResult holds the typed result of an operation in a batch, which is available when the batch is executed
+type BatchResult[ModelT any] struct {
+	batch     *Batch
+	contentId string
+	model     ModelT
+	err       error
+}
+
+// NewBatch creates an empty batch for the client
+func NewBatch(client ODataClient, format BatchFormat) *Batch {
+	return &Batch{
+		client: client.(*oDataClient),
+		format: format,
+	}
+}
+
+func (batch *Batch) nextContentId() string {
+	batch.lastId++
+	return strconv.Itoa(batch.lastId)
+}
+
+func (batch *Batch) add(operation *batchOperation) {
+	batch.items = append(batch.items, batchItem{operation: operation})
+}
+
+func (batch *Batch) getBatch() *Batch {
+	return batch
+}
+
+// ChangeSet adds a new change set to the batch
+func (batch *Batch) ChangeSet() *ChangeSet {
+	changeSet := &ChangeSet{batch: batch, id: "changeset" + strconv.Itoa(len(batch.items)+1)}
+	batch.items = append(batch.items, batchItem{changeSet: changeSet})
+	return changeSet
+}
+
+func (changeSet *ChangeSet) add(operation *batchOperation) {
+	operation.atomicityGroup = changeSet.id
+	changeSet.operations = append(changeSet.operations, operation)
+}
+
+func (changeSet *ChangeSet) getBatch() *Batch {
+	return changeSet.batch
+}
+
+func (batch *Batch) operations() []*batchOperation {
+	var operations []*batchOperation
+	for _, item := range batch.items {
+		if item.operation != nil {
+			operations = append(operations, item.operation)
+		} else {
+			operations = append(operations, item.changeSet.operations...)
+		}
+	}
+	return operations
+}
+
+// ContentId returns the id of the operation in the batch
+func (result *BatchResult[ModelT]) ContentId() string {
+	return result.contentId
+}
+
+// Entity returns a reference to the entity of the operation, like $1, which can be used in later
+// operations of the same change set, for example to navigate to the related entities of an inserted entity
+func (result *BatchResult[ModelT]) Entity() ODataEntity[ModelT] {
+	return newEntity[ModelT](result.batch.client, "$"+result.contentId)
+}
+
+// Result returns the model and the error of the operation, after the batch has been executed
+func (result *BatchResult[ModelT]) Result() (ModelT, error) {
+	if !result.batch.executed {
+		var model ModelT
+		return model, fmt.Errorf("the batch has not been executed")
+	}
+	return result.model, result.err
+}
+
+func addBatchOperation[ModelT any](queue BatchQueue, method string, path string, payload interface{}, options []RequestOption, decode func(response *http.Response, body []byte) (ModelT, error)) *BatchResult[ModelT] {
+	batch := queue.getBatch()
+	result := &BatchResult[ModelT]{batch: batch, contentId: batch.nextContentId()}
+	operation := &batchOperation{contentId: result.contentId}
+	operation.handle = func(response *http.Response, body []byte, err error) {
+		if err != nil {
+			result.err = err
+			return
+		}
+		result.model, result.err = decode(response, body)
+	}
+
+	request, err := http.NewRequest(method, batch.client.baseUrl+path, nil)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	if payload != nil {
+		if err = validateFacets(payload); err != nil {
+			result.err = err
+			return result
+		}
+		if operation.body, err = marshalPayload(payload, writePayloadLeaveOut(method, payload)...); err != nil {
+			result.err = err
+			return result
+		}
+		request.Header.Set("Content-Type", "application/json;odata.metadata=minimal")
+		request.Header.Set("Prefer", "return=representation")
+	}
+	applyRequestOptions(request, options)
+	operation.request = request
+	queue.add(operation)
+	return result
+}
+
+func decodeBatchEntity[ModelT any](response *http.Response, body []byte) (ModelT, error) {
+	model, err := decodeEntity[ModelT](body)
+	if err == nil {
+		setResponseETag(&model, response)
+	}
+	return model, err
+}
+
+func decodeBatchWrite[ModelT any](model ModelT) func(response *http.Response, body []byte) (ModelT, error) {
+	return func(response *http.Response, body []byte) (ModelT, error) {
+		if response.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(body)) == 0 {
+			setResponseETag(&model, response)
+			return model, nil
+		}
+		return decodeBatchEntity[ModelT](response, body)
+	}
+}
+
+// BatchGet adds the request of a single model to the batch
+func BatchGet[ModelT any](queue BatchQueue, entity ODataEntity[ModelT], filter ODataFilter, options ...RequestOption) *BatchResult[ModelT] {
+	path := withQueryString(entity.getPath(), filter)
+	return addBatchOperation(queue, "GET", path, nil, options, decodeBatchEntity[ModelT])
+}
+
+// BatchInsert adds the insert of a model to the batch
+func BatchInsert[ModelT any, Def ODataModelDefinition[ModelT]](queue BatchQueue, dataSet ODataDataSet[ModelT, Def], model ModelT, options ...RequestOption) *BatchResult[ModelT] {
+	return addBatchOperation(queue, "POST", dataSet.getPath(), model, options, decodeBatchWrite(model))
+}
+
+// BatchUpdate adds the update of a model with PATCH to the batch, the ETag of the model is sent as If-Match
+func BatchUpdate[ModelT any](queue BatchQueue, entity ODataEntity[ModelT], model ModelT, options ...RequestOption) *BatchResult[ModelT] {
+	return addBatchOperation(queue, "PATCH", entity.getPath(), model, withETag(model, options), decodeBatchWrite(model))
+}
+
+// BatchReplace adds the replacement of a model with PUT to the batch, the ETag of the model is sent as If-Match
+func BatchReplace[ModelT any](queue BatchQueue, entity ODataEntity[ModelT], model ModelT, options ...RequestOption) *BatchResult[ModelT] {
+	return addBatchOperation(queue, "PUT", entity.getPath(), model, withETag(model, options), decodeBatchWrite(model))
+}
+
+// BatchDelete adds the deletion of a model to the batch
+func BatchDelete[ModelT any](queue BatchQueue, entity ODataEntity[ModelT], options ...RequestOption) *BatchResult[struct{}] {
+	return addBatchOperation(queue, "DELETE", entity.getPath(), nil, options, func(response *http.Response, body []byte) (struct{}, error) {
+		return struct{}{}, nil
+	})
+}
+
+// Execute sends the batch to the service and fills the results of the operations. The returned error
+// is only about the batch request itself, the errors of the operations are returned by BatchResult.Result.
+func (batch *Batch) Execute(ctx context.Context) error {
+	if batch.executed {
+		return fmt.Errorf("the batch has already been executed")
+	}
+	var err error
+	if batch.format == BatchFormatJSON {
+		err = batch.executeJson(ctx)
+	} else {
+		err = batch.executeMultipart(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	batch.executed = true
+	return nil
+}
+
+func (batch *Batch) relativeUrl(request *http.Request) string {
+	return strings.TrimPrefix(request.URL.String(), batch.client.baseUrl)
+}
+
+// handleResponse passes the response of an operation to its result, with an *ODataError for error status codes
+func (batch *Batch) handleResponse(operation *batchOperation, response *http.Response, body []byte) {
+	if response.StatusCode >= http.StatusBadRequest {
+		operation.handle(response, body, newODataError(operation.request, response.StatusCode, body))
+		return
+	}
+	operation.handle(response, body, nil)
+}
+
+func (batch *Batch) sendBatchRequest(ctx context.Context, contentType string, body []byte) (*http.Response, []byte, error) {
+	request, err := http.NewRequestWithContext(ctx, "POST", batch.client.baseUrl+"$batch", bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("Accept", contentType)
+	return executeRawHttpRequest(*batch.client, request)
+}
+
+type jsonBatchRequest struct {
+	Id             string            `json:"id"`
+	AtomicityGroup string            `json:"atomicityGroup,omitempty"`
+	DependsOn      []string          `json:"dependsOn,omitempty"`
+	Method         string            `json:"method"`
+	Url            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           json.RawMessage   `json:"body,omitempty"`
+}
+
+type jsonBatchResponse struct {
+	Id             string            `json:"id"`
+	AtomicityGroup string            `json:"atomicityGroup"`
+	Status         int               `json:"status"`
+	Headers        map[string]string `json:"headers"`
+	Body           json.RawMessage   `json:"body"`
+}
+
+func (batch *Batch) executeJson(ctx context.Context) error {
+	operations := batch.operations()
+	requests := make([]jsonBatchRequest, len(operations))
+	for i, operation := range operations {
+		relativeUrl := batch.relativeUrl(operation.request)
+		requests[i] = jsonBatchRequest{
+			Id:             operation.contentId,
+			AtomicityGroup: operation.atomicityGroup,
+			Method:         operation.request.Method,
+			Url:            relativeUrl,
+			Headers:        map[string]string{},
+			Body:           operation.body,
+		}
+		for key := range operation.request.Header {
+			requests[i].Headers[strings.ToLower(key)] = operation.request.Header.Get(key)
+		}
+		if strings.HasPrefix(relativeUrl, "$") {
+			reference := strings.SplitN(strings.TrimPrefix(relativeUrl, "$"), "/", 2)[0]
+			requests[i].DependsOn = []string{reference}
+		}
+	}
+	body, err := json.Marshal(struct {
+		Requests []jsonBatchRequest `json:"requests"`
+	}{requests})
+	if err != nil {
+		return err
+	}
+
+	_, responseBody, err := batch.sendBatchRequest(ctx, "application/json", body)
+	if err != nil {
+		return err
+	}
+	var responseData struct {
+		Responses []jsonBatchResponse `json:"responses"`
+	}
+	if err = json.Unmarshal(responseBody, &responseData); err != nil {
+		return err
+	}
+
+	responses := map[string]jsonBatchResponse{}
+	failedGroups := map[string]jsonBatchResponse{}
+	for _, response := range responseData.Responses {
+		responses[response.Id] = response
+		if response.AtomicityGroup != "" && response.Status >= http.StatusBadRequest {
+			failedGroups[response.AtomicityGroup] = response
+		}
+	}
+	for _, operation := range operations {
+		response, ok := responses[operation.contentId]
+		if !ok {
+			response, ok = failedGroups[operation.atomicityGroup]
+		}
+		if !ok {
+			operation.handle(nil, nil, fmt.Errorf("no response for request %s in the batch", operation.contentId))
+			continue
+		}
+		httpResponse := &http.Response{StatusCode: response.Status, Header: http.Header{}}
+		for key, value := range response.Headers {
+			httpResponse.Header.Set(key, value)
+		}
+		batch.handleResponse(operation, httpResponse, response.Body)
+	}
+	return nil
+}
+
+func writeMultipartOperation(writer *multipart.Writer, operation *batchOperation, relativeUrl string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"application/http"},
+		"Content-Transfer-Encoding": {"binary"},
+		"Content-Id":                {operation.contentId},
+	})
+	if err != nil {
+		return err
+	}
+	requestLines := []string{fmt.Sprintf("%s %s HTTP/1.1", operation.request.Method, relativeUrl)}
+	headerKeys := make([]string, 0, len(operation.request.Header))
+	for key := range operation.request.Header {
+		headerKeys = append(headerKeys, key)
+	}
+	sort.Strings(headerKeys)
+	for _, key := range headerKeys {
+		requestLines = append(requestLines, key+": "+operation.request.Header.Get(key))
+	}
+	_, err = part.Write([]byte(strings.Join(requestLines, "\r\n") + "\r\n\r\n"))
+	if err == nil && len(operation.body) > 0 {
+		_, err = part.Write(operation.body)
+	}
+	return err
+}
+
+func (batch *Batch) executeMultipart(ctx context.Context) error {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	for _, item := range batch.items {
+		if item.operation != nil {
+			if err := writeMultipartOperation(writer, item.operation, batch.relativeUrl(item.operation.request)); err != nil {
+				return err
+			}
+			continue
+		}
+		changeSetBuf := &bytes.Buffer{}
+		changeSetWriter := multipart.NewWriter(changeSetBuf)
+		for _, operation := range item.changeSet.operations {
+			if err := writeMultipartOperation(changeSetWriter, operation, batch.relativeUrl(operation.request)); err != nil {
+				return err
+			}
+		}
+		if err := changeSetWriter.Close(); err != nil {
+			return err
+		}
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {"multipart/mixed; boundary=" + changeSetWriter.Boundary()},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = part.Write(changeSetBuf.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	response, responseBody, err := batch.sendBatchRequest(ctx, "multipart/mixed; boundary="+writer.Boundary(), buf.Bytes())
+	if err != nil {
+		return err
+	}
+	parts, err := readMultipartResponses(response.Header.Get("Content-Type"), bytes.NewReader(responseBody))
+	if err != nil {
+		return err
+	}
+
+	i := 0
+	for _, item := range batch.items {
+		if i >= len(parts) {
+			for _, operation := range itemOperations(item) {
+				operation.handle(nil, nil, fmt.Errorf("no response for request %s in the batch", operation.contentId))
+			}
+			continue
+		}
+		part := parts[i]
+		i++
+		if item.operation != nil {
+			batch.handleResponse(item.operation, part.response, part.body)
+			continue
+		}
+		// a failed change set is answered with a single response for all its operations
+		if part.changeSet == nil {
+			for _, operation := range item.changeSet.operations {
+				batch.handleResponse(operation, part.response, part.body)
+			}
+			continue
+		}
+		for j, operation := range item.changeSet.operations {
+			changeSetPart := findMultipartResponse(part.changeSet, operation.contentId, j)
+			if changeSetPart == nil {
+				operation.handle(nil, nil, fmt.Errorf("no response for request %s in the batch", operation.contentId))
+				continue
+			}
+			batch.handleResponse(operation, changeSetPart.response, changeSetPart.body)
+		}
+	}
+	return nil
+}
+
+func itemOperations(item batchItem) []*batchOperation {
+	if item.operation != nil {
+		return []*batchOperation{item.operation}
+	}
+	return item.changeSet.operations
+}
+
+type multipartResponse struct {
+	contentId string
+	response  *http.Response
+	body      []byte
+	changeSet []multipartResponse
+}
+
+func findMultipartResponse(parts []multipartResponse, contentId string, index int) *multipartResponse {
+	for i := range parts {
+		if parts[i].contentId == contentId {
+			return &parts[i]
+		}
+	}
+	if index < len(parts) && parts[index].contentId == "" {
+		return &parts[index]
+	}
+	return nil
+}
+
+func readMultipartResponses(contentType string, body io.Reader) ([]multipartResponse, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("unexpected content type of batch response: %s", contentType)
+	}
+	reader := multipart.NewReader(body, params["boundary"])
+	var responses []multipartResponse
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return responses, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		partContentType := part.Header.Get("Content-Type")
+		if strings.HasPrefix(partContentType, "multipart/") {
+			changeSet, err := readMultipartResponses(partContentType, part)
+			if err != nil {
+				return nil, err
+			}
+			responses = append(responses, multipartResponse{changeSet: changeSet})
+			continue
+		}
+		response, err := http.ReadResponse(bufio.NewReader(part), nil)
+		if err != nil {
+			return nil, err
+		}
+		responseBody, err := ioutil.ReadAll(response.Body)
+		_ = response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, multipartResponse{
+			contentId: part.Header.Get("Content-Id"),
+			response:  response,
+			body:      responseBody,
+		})
+	}
+}
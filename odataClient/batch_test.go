@@ -0,0 +1,169 @@
+package odataClient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatch_Multipart(t *testing.T) {
+	var requestLines []string
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/$batch" || request.Method != "POST" {
+			writer.WriteHeader(404)
+			return
+		}
+		_, params, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+		reader := multipart.NewReader(request.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Type"), "multipart/mixed") {
+				_, changeSetParams, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+				changeSetReader := multipart.NewReader(part, changeSetParams["boundary"])
+				for {
+					changeSetPart, err := changeSetReader.NextPart()
+					if err != nil {
+						break
+					}
+					line, _ := bufio.NewReader(changeSetPart).ReadString('\n')
+					requestLines = append(requestLines, changeSetPart.Header.Get("Content-ID")+" "+strings.TrimSpace(line))
+				}
+				continue
+			}
+			line, _ := bufio.NewReader(part).ReadString('\n')
+			requestLines = append(requestLines, part.Header.Get("Content-ID")+" "+strings.TrimSpace(line))
+		}
+
+		writer.Header().Set("Content-Type", "multipart/mixed; boundary=batch_response")
+		_, _ = writer.Write([]byte(strings.ReplaceAll(`--batch_response
+Content-Type: application/http
+
+HTTP/1.1 200 OK
+Content-Type: application/json
+ETag: W/"1"
+
+{"Id":5,"Name":"Donald Duck"}
+--batch_response
+Content-Type: multipart/mixed; boundary=changeset_response
+
+--changeset_response
+Content-Type: application/http
+Content-ID: 2
+
+HTTP/1.1 201 Created
+Content-Type: application/json
+
+{"Id":6,"Name":"Daisy Duck"}
+--changeset_response
+Content-Type: application/http
+Content-ID: 3
+
+HTTP/1.1 201 Created
+Content-Type: application/json
+
+{"TripId":1,"Name":"Trip"}
+--changeset_response--
+--batch_response
+Content-Type: application/http
+
+HTTP/1.1 404 Not Found
+Content-Type: application/json
+
+{"error":{"code":"NotFound","message":"No such person"}}
+--batch_response--
+`, "\n", "\r\n")))
+	}))
+	defer testServer.Close()
+
+	client := New(testServer.URL)
+	people := newTestModelDefinition(client).DataSet()
+	batch := NewBatch(client, BatchFormatMultipart)
+
+	single := BatchGet(batch, people.Entity(Key(5)), ODataFilter{Select: []string{"Name"}})
+	changeSet := batch.ChangeSet()
+	inserted := BatchInsert(changeSet, people, testModel{Name: "Daisy Duck"})
+	trip := BatchInsert(changeSet, Navigate[testTrip](inserted.Entity(), "Trips"), testTrip{Name: "Trip"})
+	deleted := BatchDelete(batch, people.Entity(Key(7)))
+
+	_, err := single.Result()
+	assert.Error(t, err)
+
+	assert.NoError(t, batch.Execute(context.Background()))
+	assert.Equal(t, []string{
+		"1 GET People(5)?%24select=Name HTTP/1.1",
+		"2 POST People HTTP/1.1",
+		"3 POST $2/Trips HTTP/1.1",
+		"4 DELETE People(7) HTTP/1.1",
+	}, requestLines)
+
+	model, err := single.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "Donald Duck", model.Name)
+	model, err = inserted.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 6, model.Id)
+	tripModel, err := trip.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tripModel.TripId)
+	_, err = deleted.Result()
+	assert.True(t, IsNotFound(err))
+}
+
+func TestBatch_JSON(t *testing.T) {
+	var requests []jsonBatchRequest
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		var requestData struct {
+			Requests []jsonBatchRequest `json:"requests"`
+		}
+		_ = json.Unmarshal(body, &requestData)
+		requests = requestData.Requests
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(writer, `{"responses":[
+			{"id":"1","atomicityGroup":"changeset1","status":412,"body":{"error":{"code":"","message":"ETag mismatch"}}},
+			{"id":"3","status":200,"headers":{"etag":"W/\"3\""},"body":{"Id":3,"Name":"Scrooge"}}
+		]}`)
+	}))
+	defer testServer.Close()
+
+	client := New(testServer.URL)
+	people := newTestModelDefinition(client).DataSet()
+	batch := NewBatch(client, BatchFormatJSON)
+	changeSet := batch.ChangeSet()
+	updated := BatchUpdate(changeSet, people.Entity(Key(1)), testModel{Id: 1, Name: "Foo"}, IfMatch(`W/"1"`))
+	replaced := BatchReplace(changeSet, people.Entity(Key(2)), testModel{Id: 2, Name: "Bar"})
+	single := BatchGet(batch, newTestETagDataSet(client).Entity(Key(3)), ODataFilter{})
+
+	assert.NoError(t, batch.Execute(context.Background()))
+	assert.Len(t, requests, 3)
+	assert.Equal(t, "PATCH", requests[0].Method)
+	assert.Equal(t, "People(1)", requests[0].Url)
+	assert.Equal(t, "changeset1", requests[0].AtomicityGroup)
+	assert.Equal(t, `W/"1"`, requests[0].Headers["if-match"])
+	assert.JSONEq(t, `{"Id":1,"Name":"Foo","Number":"","ParentId":null,"Description":null}`, string(requests[0].Body))
+	assert.Equal(t, "PUT", requests[1].Method)
+	assert.Equal(t, "GET", requests[2].Method)
+	assert.Equal(t, "", requests[2].AtomicityGroup)
+
+	_, err := updated.Result()
+	assert.True(t, IsPreconditionFailed(err))
+	_, err = replaced.Result()
+	assert.True(t, IsPreconditionFailed(err))
+	model, err := single.Result()
+	assert.NoError(t, err)
+	assert.Equal(t, "Scrooge", model.Name)
+	assert.Equal(t, `W/"3"`, model.ETag)
+	assert.Error(t, batch.Execute(context.Background()))
+}
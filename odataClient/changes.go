@@ -0,0 +1,40 @@
+package odataClient
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Changes returns the JSON properties of the modified model which differ from the original model.
+// Properties set to null in the modified model are included as explicit nulls. Properties which are
+// left out of the modified JSON, for example by omitempty, are not included.
+func Changes[ModelT any](original ModelT, modified ModelT) (map[string]json.RawMessage, error) {
+	originalProperties, err := jsonProperties(original)
+	if err != nil {
+		return nil, err
+	}
+	modifiedProperties, err := jsonProperties(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]json.RawMessage{}
+	for key, value := range modifiedProperties {
+		originalValue, ok := originalProperties[key]
+		if !ok || !bytes.Equal(originalValue, value) {
+			changes[key] = value
+		}
+	}
+	return changes, nil
+}
+
+func jsonProperties(model interface{}) (map[string]json.RawMessage, error) {
+	jsonData, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]json.RawMessage{}
+	err = json.Unmarshal(jsonData, &properties)
+	delete(properties, "@odata.etag")
+	return properties, err
+}
@@ -0,0 +1,66 @@
+package odataClient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Uffe-Code/go-nullable/nullable"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	original := testModel{
+		Id:          5,
+		Number:      "001",
+		Name:        "Donald Duck",
+		ParentId:    nullable.Value(3),
+		Description: nullable.Null[string](),
+	}
+	modified := original
+	modified.Name = "Daisy Duck"
+	modified.ParentId = nullable.Null[int]()
+	modified.Description = nullable.Value("Test description")
+
+	changes, err := Changes(original, modified)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, `"Daisy Duck"`, string(changes["Name"]))
+	assert.Equal(t, `null`, string(changes["ParentId"]))
+	assert.Equal(t, `"Test description"`, string(changes["Description"]))
+
+	changes, err = Changes(original, original)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestOdataDataSet_UpdateChanges(t *testing.T) {
+	requests := 0
+	var body map[string]interface{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		if request.URL.Path != "/People(5)" || request.Method != "PATCH" {
+			writer.WriteHeader(404)
+			return
+		}
+		_ = json.NewDecoder(request.Body).Decode(&body)
+		writer.WriteHeader(204)
+	}))
+	defer testServer.Close()
+
+	dataSet := newTestModelDefinition(New(testServer.URL)).DataSet()
+	original := testModel{Id: 5, Name: "Donald Duck", ParentId: nullable.Value(3)}
+	modified := original
+	modified.Name = "Daisy Duck"
+	modified.ParentId = nullable.Null[int]()
+
+	res, err := dataSet.UpdateChanges(context.Background(), Key(5), original, modified)
+	assert.NoError(t, err)
+	assert.Equal(t, modified, res)
+	assert.Equal(t, map[string]interface{}{"Name": "Daisy Duck", "ParentId": nil}, body)
+
+	_, err = dataSet.UpdateChanges(context.Background(), Key(5), modified, modified)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
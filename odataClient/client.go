@@ -1,9 +1,11 @@
 package odataClient
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -12,12 +14,20 @@ type oDataClient struct {
 	headers         map[string]string
 	httpClient      *http.Client
 	defaultPageSize int
+	keyAsSegment    bool
 }
 
 // ODataClient represents a connection to the OData REST API
 type ODataClient interface {
 	Wrapper
 	AddHeader(key string, value string)
+	SetKeyAsSegment(enabled bool)
+	Metadata(ctx context.Context) ([]byte, error)
+	// MetadataUrl returns the url of the $metadata document of the service
+	MetadataUrl() string
+	// MetadataDocument fetches an EDMX document, like one referenced by $metadata, with the headers and the HTTP client
+	// of the client. A relative url is resolved against the url of $metadata.
+	MetadataDocument(ctx context.Context, documentUrl string) ([]byte, error)
 }
 
 // Wrapper represents a wrapper around the OData client if you have build own code around the OData itself, for authentication etc
@@ -49,26 +59,75 @@ func (client *oDataClient) AddHeader(key string, value string) {
 	client.headers[strings.ToLower(key)] = value
 }
 
+// SetKeyAsSegment will address entities as People/russellwhyte instead of People('russellwhyte'),
+// for services using the key-as-segment convention
+func (client *oDataClient) SetKeyAsSegment(enabled bool) {
+	client.keyAsSegment = enabled
+}
+
+// Metadata fetches the $metadata document of the service, with the headers of the client
+func (client *oDataClient) Metadata(ctx context.Context) ([]byte, error) {
+	return client.MetadataDocument(ctx, client.MetadataUrl())
+}
+
+func (client *oDataClient) MetadataUrl() string {
+	return client.baseUrl + "$metadata"
+}
+
+func (client *oDataClient) MetadataDocument(ctx context.Context, documentUrl string) ([]byte, error) {
+	base, err := url.Parse(client.MetadataUrl())
+	if err != nil {
+		return nil, err
+	}
+	reference, err := url.Parse(documentUrl)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, "GET", base.ResolveReference(reference).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/xml")
+	_, body, err := executeRawHttpRequest(*client, request)
+	return body, err
+}
+
 // ODataClient will return self, so it also works as a wrapper in case we don't have a wrapper
 func (client *oDataClient) ODataClient() ODataClient {
 	return client
 }
 
+// mapHeadersToRequest adds the client headers, headers already set on the request take precedence
 func (client oDataClient) mapHeadersToRequest(req *http.Request) {
 	for key, value := range client.headers {
-		req.Header.Set(key, value)
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
 	}
 }
 
-func executeHttpRequest[T interface{}](client oDataClient, req *http.Request) (T, error) {
+// executeRawHttpRequest sends the request and reads the response body. Any status code of 400 and
+// above, and 304 Not Modified, is returned as an *ODataError
+func executeRawHttpRequest(client oDataClient, req *http.Request) (*http.Response, []byte, error) {
 	client.mapHeadersToRequest(req)
 	response, err := client.httpClient.Do(req)
-	var responseData T
 	if err != nil {
-		return responseData, err
+		return nil, nil, err
 	}
 	defer func() { _ = response.Body.Close() }()
 	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return response, nil, err
+	}
+	if response.StatusCode >= http.StatusBadRequest || response.StatusCode == http.StatusNotModified {
+		return response, body, newODataError(req, response.StatusCode, body)
+	}
+	return response, body, nil
+}
+
+func executeHttpRequest[T interface{}](client oDataClient, req *http.Request) (T, error) {
+	var responseData T
+	_, body, err := executeRawHttpRequest(client, req)
 	if err != nil {
 		return responseData, err
 	}
@@ -1,7 +1,10 @@
 package odataClient
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -10,3 +13,40 @@ func TestODataClient_AddHeader(t *testing.T) {
 	client.AddHeader("X-Foo", "Bar")
 	assert.Equal(t, "Bar", client.(*oDataClient).headers["x-foo"])
 }
+
+func TestODataClient_mapHeadersToRequest(t *testing.T) {
+	client := New("http://test.api/")
+	client.AddHeader("Prefer", "return=representation")
+	client.AddHeader("X-Foo", "Bar")
+	request, _ := http.NewRequest("GET", "http://test.api/People", nil)
+	request.Header.Set("Prefer", "return=minimal")
+	client.(*oDataClient).mapHeadersToRequest(request)
+	assert.Equal(t, "return=minimal", request.Header.Get("Prefer"))
+	assert.Equal(t, "Bar", request.Header.Get("X-Foo"))
+}
+
+func TestODataClient_Metadata(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if (request.URL.Path != "/service/$metadata" && request.URL.Path != "/service/common/Common.xml") || request.Header.Get("Accept") != "application/xml" || request.Header.Get("Authorization") != "Bearer secret" {
+			writer.WriteHeader(401)
+			return
+		}
+		_, _ = writer.Write([]byte(`<edmx:Edmx Version="4.0"/>`))
+	}))
+	defer testServer.Close()
+
+	client := New(testServer.URL + "/service")
+	_, err := client.Metadata(context.Background())
+	assert.True(t, IsUnauthorized(err))
+
+	client.AddHeader("Authorization", "Bearer secret")
+	metadata, err := client.Metadata(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, `<edmx:Edmx Version="4.0"/>`, string(metadata))
+
+	assert.Equal(t, testServer.URL+"/service/$metadata", client.MetadataUrl())
+	_, err = client.MetadataDocument(context.Background(), "common/Common.xml")
+	assert.NoError(t, err)
+	_, err = client.MetadataDocument(context.Background(), testServer.URL+"/service/common/Common.xml")
+	assert.NoError(t, err)
+}
@@ -1,11 +1,9 @@
 package odataClient
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"net/url"
 )
 
 type odataDataSet[ModelT any, Def ODataModelDefinition[ModelT]] struct {
@@ -14,14 +12,21 @@ type odataDataSet[ModelT any, Def ODataModelDefinition[ModelT]] struct {
 }
 
 type ODataDataSet[ModelT any, Def ODataModelDefinition[ModelT]] interface {
-	Single(id string) (ModelT, error)
-	List(filter ODataFilter) (<-chan ModelT, <-chan error)
-	Insert(model ModelT) (ModelT, error)
-	Update(id string, model ModelT) (ModelT, error)
-	Delete(id string) error
-
+	Single(ctx context.Context, key EntityKey, filter ODataFilter, options ...RequestOption) (ModelT, error)
+	List(ctx context.Context, filter ODataFilter) (<-chan ModelT, <-chan error)
+	Count(ctx context.Context, filter ODataFilter) (int, error)
+	Insert(ctx context.Context, model ModelT, options ...RequestOption) (ModelT, error)
+	Update(ctx context.Context, key EntityKey, model ModelT, options ...RequestOption) (ModelT, error)
+	Replace(ctx context.Context, key EntityKey, model ModelT, options ...RequestOption) (ModelT, error)
+	UpdateChanges(ctx context.Context, key EntityKey, original ModelT, modified ModelT, options ...RequestOption) (ModelT, error)
+	Delete(ctx context.Context, key EntityKey, options ...RequestOption) error
+	DeleteModel(ctx context.Context, key EntityKey, model ModelT, options ...RequestOption) error
+	Entity(key EntityKey) ODataEntity[ModelT]
+
+	getClient() *oDataClient
+	getPath() string
 	getCollectionUrl() string
-	getSingleUrl(modelId string) string
+	getSingleUrl(key EntityKey) string
 }
 
 func NewDataSet[ModelT any, Def ODataModelDefinition[ModelT]](client ODataClient, modelDefinition Def) ODataDataSet[ModelT, Def] {
@@ -31,16 +36,29 @@ func NewDataSet[ModelT any, Def ODataModelDefinition[ModelT]](client ODataClient
 	}
 }
 
+func (dataSet odataDataSet[ModelT, Def]) getClient() *oDataClient {
+	return dataSet.client
+}
+
+func (dataSet odataDataSet[ModelT, Def]) getPath() string {
+	return dataSet.modelDefinition.Url()
+}
+
 func (dataSet odataDataSet[ModelT, Def]) getCollectionUrl() string {
-	return dataSet.client.baseUrl + dataSet.modelDefinition.Url()
+	return dataSet.client.baseUrl + dataSet.getPath()
 }
 
-func (dataSet odataDataSet[ModelT, Def]) getSingleUrl(modelId string) string {
-	return fmt.Sprintf("%s(%s)", dataSet.client.baseUrl+dataSet.modelDefinition.Url(), modelId)
+func (dataSet odataDataSet[ModelT, Def]) getSingleUrl(key EntityKey) string {
+	return dataSet.client.baseUrl + dataSet.getEntityPath(key)
 }
 
-type apiSingleResponse[T interface{}] struct {
-	Value T `json:"value"`
+func (dataSet odataDataSet[ModelT, Def]) getEntityPath(key EntityKey) string {
+	return dataSet.getPath() + key.segment(dataSet.client.keyAsSegment)
+}
+
+// Entity returns the single entity with the key, which can be used for navigation
+func (dataSet odataDataSet[ModelT, Def]) Entity(key EntityKey) ODataEntity[ModelT] {
+	return newEntity[ModelT](dataSet.client, dataSet.getEntityPath(key))
 }
 
 type apiMultiResponse[T interface{}] struct {
@@ -48,46 +66,61 @@ type apiMultiResponse[T interface{}] struct {
 	NextLink string `json:"@odata.nextLink"`
 }
 
-// ODataFilter represents a OData Filter query
-type ODataFilter struct {
-	Filter string
+// UnmarshalJSON decodes each value with decodeValue, so derived types are decoded by their @odata.type
+func (response *apiMultiResponse[T]) UnmarshalJSON(data []byte) error {
+	var rawResponse struct {
+		Value    []json.RawMessage `json:"value"`
+		NextLink string            `json:"@odata.nextLink"`
+	}
+	if err := json.Unmarshal(data, &rawResponse); err != nil {
+		return err
+	}
+	response.NextLink = rawResponse.NextLink
+	response.Value = make([]T, len(rawResponse.Value))
+	for i, rawValue := range rawResponse.Value {
+		value, err := decodeValue[T](rawValue)
+		if err != nil {
+			return err
+		}
+		response.Value[i] = value
+	}
+	return nil
 }
 
-func (filter ODataFilter) toQueryString() string {
-	queryStrings := url.Values{}
-	if filter.Filter != "" {
-		queryStrings.Add("$filter", filter.Filter)
+func withQueryString(requestUrl string, filter ODataFilter) string {
+	queryString := filter.toQueryString()
+	if queryString == "" {
+		return requestUrl
 	}
-	return queryStrings.Encode()
+	return requestUrl + "?" + queryString
 }
 
-// Single model from the API by ID
-func (dataSet odataDataSet[ModelT, Def]) Single(id string) (ModelT, error) {
-	requestUrl := dataSet.getSingleUrl(id)
-	request, err := http.NewRequest("GET", requestUrl, nil)
-	var responseModel ModelT
-	if err != nil {
-		return responseModel, err
-	}
-	responseData, err := executeHttpRequest[apiSingleResponse[ModelT]](*dataSet.client, request)
-	if err != nil {
-		return responseModel, err
-	}
-	return responseData.Value, nil
+// Single model from the API by key, the filter can be used to $select and $expand properties
+func (dataSet odataDataSet[ModelT, Def]) Single(ctx context.Context, key EntityKey, filter ODataFilter, options ...RequestOption) (ModelT, error) {
+	return dataSet.Entity(key).Get(ctx, filter, options...)
 }
 
-// List data from the API
-func (dataSet odataDataSet[ModelT, Def]) List(filter ODataFilter) (<-chan ModelT, <-chan error) {
+// List data from the API. The data is fetched page by page, following the @odata.nextLink of the
+// service when given. When filter.Top is set, no more than that amount of models are returned.
+// Cancelling the context stops the fetching, and the context error is sent on the error channel.
+func (dataSet odataDataSet[ModelT, Def]) List(ctx context.Context, filter ODataFilter) (<-chan ModelT, <-chan error) {
 	ch := make(chan ModelT)
-	errs := make(chan error)
+	errs := make(chan error, 1)
 
 	go func() {
 		defer close(ch)
 		defer close(errs)
 
-		requestUrl := fmt.Sprintf("%s?$top=%d&$skip=0&%s", dataSet.getCollectionUrl(), dataSet.client.defaultPageSize, filter.toQueryString())
+		remaining := filter.Top
+		pageFilter := filter
+		pageFilter.Top = dataSet.client.defaultPageSize
+		if remaining > 0 && remaining < pageFilter.Top {
+			pageFilter.Top = remaining
+		}
+
+		requestUrl := withQueryString(dataSet.getCollectionUrl(), pageFilter)
 		for requestUrl != "" {
-			request, err := http.NewRequest("GET", requestUrl, nil)
+			request, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
 			if err != nil {
 				errs <- err
 				return
@@ -99,64 +132,91 @@ func (dataSet odataDataSet[ModelT, Def]) List(filter ODataFilter) (<-chan ModelT
 			}
 
 			for _, model := range responseData.Value {
-				ch <- model
+				select {
+				case ch <- model:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+				if remaining > 0 {
+					remaining--
+					if remaining == 0 {
+						return
+					}
+				}
+			}
+			if responseData.NextLink != "" {
+				requestUrl = responseData.NextLink
+				continue
 			}
-			if len(responseData.Value) < dataSet.client.defaultPageSize {
+			if len(responseData.Value) < pageFilter.Top {
 				return
 			}
-			requestUrl = responseData.NextLink
+			pageFilter.Skip += len(responseData.Value)
+			if remaining > 0 && remaining < pageFilter.Top {
+				pageFilter.Top = remaining
+			}
+			requestUrl = withQueryString(dataSet.getCollectionUrl(), pageFilter)
 		}
 	}()
 
 	return ch, errs
 }
 
-// Insert a model to the API
-func (dataSet odataDataSet[ModelT, Def]) Insert(model ModelT) (ModelT, error) {
-	requestUrl := dataSet.getCollectionUrl()
-	var result ModelT
-	jsonData, err := json.Marshal(model)
-	if err != nil {
-		return result, err
+// Count the models matching the $filter and $search of the filter
+func (dataSet odataDataSet[ModelT, Def]) Count(ctx context.Context, filter ODataFilter) (int, error) {
+	countFilter := ODataFilter{
+		Filter:     filter.Filter,
+		Expression: filter.Expression,
+		Search:     filter.Search,
+		Custom:     filter.Custom,
 	}
-	request, err := http.NewRequest("POST", requestUrl, bytes.NewReader(jsonData))
+	requestUrl := withQueryString(dataSet.getCollectionUrl()+"/$count", countFilter)
+	request, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
 	if err != nil {
-		return result, err
+		return 0, err
 	}
-	request.Header.Set("Content-Type", "application/json;odata.metadata=minimal")
-	request.Header.Set("Prefer", "return=representation")
-	return executeHttpRequest[ModelT](*dataSet.client, request)
+	return executeHttpRequest[int](*dataSet.client, request)
 }
 
-// Update a model in the API
-func (dataSet odataDataSet[ModelT, Def]) Update(id string, model ModelT) (ModelT, error) {
-	requestUrl := dataSet.getSingleUrl(id)
-	var result ModelT
-	jsonData, err := json.Marshal(model)
-	if err != nil {
-		return result, err
-	}
-	request, err := http.NewRequest("POST", requestUrl, bytes.NewReader(jsonData))
-	if err != nil {
-		return result, err
-	}
-	request.Header.Set("Content-Type", "application/json;odata.metadata=minimal")
-	request.Header.Set("Prefer", "return=representation")
-	return executeHttpRequest[ModelT](*dataSet.client, request)
+// Insert a model to the API
+func (dataSet odataDataSet[ModelT, Def]) Insert(ctx context.Context, model ModelT, options ...RequestOption) (ModelT, error) {
+	return writeModel(ctx, *dataSet.client, "POST", dataSet.getCollectionUrl(), model, model, options)
 }
 
-// Delete a model from the API
-func (dataSet odataDataSet[ModelT, Def]) Delete(id string) error {
-	requestUrl := dataSet.getSingleUrl(id)
-	request, err := http.NewRequest("DELETE", requestUrl, nil)
-	if err != nil {
-		return err
-	}
-	dataSet.client.mapHeadersToRequest(request)
-	response, err := dataSet.client.httpClient.Do(request)
+// Update a model in the API with PATCH, so the properties of the model are merged into the stored model.
+// The ETag of the model is sent as If-Match.
+func (dataSet odataDataSet[ModelT, Def]) Update(ctx context.Context, key EntityKey, model ModelT, options ...RequestOption) (ModelT, error) {
+	return dataSet.Entity(key).Update(ctx, model, options...)
+}
+
+// Replace a model in the API with PUT, so properties missing from the model are reset to their default values.
+// The ETag of the model is sent as If-Match.
+func (dataSet odataDataSet[ModelT, Def]) Replace(ctx context.Context, key EntityKey, model ModelT, options ...RequestOption) (ModelT, error) {
+	return dataSet.Entity(key).Replace(ctx, model, options...)
+}
+
+// UpdateChanges updates the model in the API with PATCH, sending only the properties where the modified
+// model differs from the original model. If nothing has changed, no request is sent. The ETag of the
+// original model is sent as If-Match.
+func (dataSet odataDataSet[ModelT, Def]) UpdateChanges(ctx context.Context, key EntityKey, original ModelT, modified ModelT, options ...RequestOption) (ModelT, error) {
+	return dataSet.Entity(key).UpdateChanges(ctx, original, modified, options...)
+}
+
+// Delete a model from the API by its key. No ETag is known, use IfMatch to only delete the model if it is unchanged,
+// or DeleteModel to send the ETag of a model which was read before.
+func (dataSet odataDataSet[ModelT, Def]) Delete(ctx context.Context, key EntityKey, options ...RequestOption) error {
+	requestUrl := dataSet.getSingleUrl(key)
+	request, err := http.NewRequestWithContext(ctx, "DELETE", requestUrl, nil)
 	if err != nil {
 		return err
 	}
-	_ = response.Body.Close()
-	return nil
+	applyRequestOptions(request, options)
+	_, _, err = executeRawHttpRequest(*dataSet.client, request)
+	return err
+}
+
+// DeleteModel deletes the model from the API, the ETag of the model is sent as If-Match
+func (dataSet odataDataSet[ModelT, Def]) DeleteModel(ctx context.Context, key EntityKey, model ModelT, options ...RequestOption) error {
+	return dataSet.Delete(ctx, key, withETag(model, options)...)
 }
@@ -2,11 +2,14 @@ package odataClient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"github.com/Uffe-Code/go-nullable/nullable"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 )
 
@@ -42,14 +45,22 @@ func TestNewDataSet(t *testing.T) {
 	client := New("http://test.api/")
 	dataSet := newTestModelDefinition(client).DataSet()
 	assert.Equal(t, "http://test.api/People", dataSet.getCollectionUrl())
-	assert.Equal(t, "http://test.api/People(5)", dataSet.getSingleUrl("5"))
+	assert.Equal(t, "http://test.api/People(5)", dataSet.getSingleUrl(Key(5)))
+	assert.Equal(t, "http://test.api/People('foo')", dataSet.getSingleUrl(Key("foo")))
+}
+
+func TestNewDataSet_KeyAsSegment(t *testing.T) {
+	client := New("http://test.api/")
+	client.SetKeyAsSegment(true)
+	dataSet := newTestModelDefinition(client).DataSet()
+	assert.Equal(t, "http://test.api/People/foo", dataSet.getSingleUrl(Key("foo")))
 }
 
 func TestNewDataSet_WithoutSlash(t *testing.T) {
 	client := New("http://test.api")
 	dataSet := newTestModelDefinition(client).DataSet()
 	assert.Equal(t, "http://test.api/People", dataSet.getCollectionUrl())
-	assert.Equal(t, "http://test.api/People(5)", dataSet.getSingleUrl("5"))
+	assert.Equal(t, "http://test.api/People(5)", dataSet.getSingleUrl(Key(5)))
 }
 
 func TestOdataDataSet_Single(t *testing.T) {
@@ -77,7 +88,7 @@ func TestOdataDataSet_Single(t *testing.T) {
 	client := New(testServer.URL)
 	def := newTestModelDefinition(client)
 	dataSet := def.DataSet()
-	model, err := dataSet.Single("5")
+	model, err := dataSet.Single(context.Background(), Key(5), ODataFilter{})
 	assert.NoError(t, err)
 	assert.Equal(t, 5, model.Id)
 	assert.Equal(t, "002", model.Number)
@@ -121,7 +132,7 @@ func TestOdataDataSet_List(t *testing.T) {
 	client := New(testServer.URL)
 	def := newTestModelDefinition(client)
 	dataSet := def.DataSet()
-	models, _ := dataSet.List(ODataFilter{})
+	models, _ := dataSet.List(context.Background(), ODataFilter{})
 
 	i := 0
 	for model := range models {
@@ -169,7 +180,7 @@ func Test_Insert(t *testing.T) {
 		ParentId:    nullable.Null[int](),
 		Description: nullable.Null[string](),
 	}
-	res, err := dataSet.Insert(model)
+	res, err := dataSet.Insert(context.Background(), model)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, res.Id)
 	assert.False(t, res.ParentId.IsValid)
@@ -182,7 +193,44 @@ func Test_Update(t *testing.T) {
 			writer.WriteHeader(404)
 			return
 		}
-		if request.Method != "POST" {
+		if request.Method != "PATCH" {
+			writer.WriteHeader(404)
+			panic("wrong request method " + request.Method)
+		}
+		writer.WriteHeader(200)
+		buf := &bytes.Buffer{}
+		_, err := buf.ReadFrom(request.Body)
+		if err != nil {
+			panic(err.Error())
+		}
+		_, _ = writer.Write(buf.Bytes())
+	}))
+	defer testServer.Close()
+
+	client := New(testServer.URL)
+	def := newTestModelDefinition(client)
+	dataSet := def.DataSet()
+	model := testModel{
+		Id:          5,
+		Number:      "1234",
+		Name:        "FooBar",
+		ParentId:    nullable.Null[int](),
+		Description: nullable.Null[string](),
+	}
+	res, err := dataSet.Update(context.Background(), Key(5), model)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, res.Id)
+	assert.False(t, res.ParentId.IsValid)
+	assert.Equal(t, "FooBar", res.Name)
+}
+
+func Test_Replace(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/People(5)" {
+			writer.WriteHeader(404)
+			return
+		}
+		if request.Method != "PUT" {
 			writer.WriteHeader(404)
 			panic("wrong request method " + request.Method)
 		}
@@ -206,9 +254,100 @@ func Test_Update(t *testing.T) {
 		ParentId:    nullable.Null[int](),
 		Description: nullable.Null[string](),
 	}
-	res, err := dataSet.Update("5", model)
+	res, err := dataSet.Replace(context.Background(), Key(5), model)
 	assert.NoError(t, err)
 	assert.Equal(t, 5, res.Id)
 	assert.False(t, res.ParentId.IsValid)
 	assert.Equal(t, "FooBar", res.Name)
 }
+
+func Test_Update_noContent(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != "PATCH" || request.Header.Get("Prefer") != "return=minimal" {
+			writer.WriteHeader(400)
+			return
+		}
+		writer.WriteHeader(204)
+	}))
+	defer testServer.Close()
+
+	dataSet := newTestModelDefinition(New(testServer.URL)).DataSet()
+	model := testModel{Id: 5, Name: "FooBar"}
+	res, err := dataSet.Update(context.Background(), Key(5), model, ReturnMinimal())
+	assert.NoError(t, err)
+	assert.Equal(t, model, res)
+}
+
+func TestOdataDataSet_List_paging(t *testing.T) {
+	var requestedQueries []url.Values
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		query := request.URL.Query()
+		requestedQueries = append(requestedQueries, query)
+		top, _ := strconv.Atoi(query.Get("$top"))
+		skip, _ := strconv.Atoi(query.Get("$skip"))
+		var models []testModel
+		for i := skip; i < skip+top && i < 5; i++ {
+			models = append(models, testModel{Id: i})
+		}
+		data, _ := json.Marshal(struct {
+			Value []testModel `json:"value"`
+		}{Value: models})
+		_, _ = writer.Write(data)
+	}))
+	defer testServer.Close()
+
+	client := New(testServer.URL)
+	client.(*oDataClient).defaultPageSize = 2
+	dataSet := newTestModelDefinition(client).DataSet()
+	models, _ := dataSet.List(context.Background(), ODataFilter{Skip: 1, Top: 3, Select: []string{"Id"}})
+
+	var ids []int
+	for model := range models {
+		ids = append(ids, model.Id)
+	}
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.Len(t, requestedQueries, 2)
+	assert.Equal(t, "2", requestedQueries[0].Get("$top"))
+	assert.Equal(t, "1", requestedQueries[0].Get("$skip"))
+	assert.Equal(t, "Id", requestedQueries[0].Get("$select"))
+	assert.Equal(t, "1", requestedQueries[1].Get("$top"))
+	assert.Equal(t, "3", requestedQueries[1].Get("$skip"))
+}
+
+func TestOdataDataSet_Count(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/People/$count" || request.URL.Query().Get("$filter") != "Age gt 5" {
+			writer.WriteHeader(404)
+			return
+		}
+		_, _ = writer.Write([]byte("42"))
+	}))
+	defer testServer.Close()
+
+	dataSet := newTestModelDefinition(New(testServer.URL)).DataSet()
+	count, err := dataSet.Count(context.Background(), ODataFilter{Expression: Gt("Age", 5), Top: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func TestOdataDataSet_List_cancel(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		data, _ := json.Marshal(struct {
+			Value []testModel `json:"value"`
+		}{Value: []testModel{{Id: 1}, {Id: 2}, {Id: 3}}})
+		_, _ = writer.Write(data)
+	}))
+	defer testServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dataSet := newTestModelDefinition(New(testServer.URL)).DataSet()
+	models, errs := dataSet.List(ctx, ODataFilter{})
+
+	model := <-models
+	assert.Equal(t, 1, model.Id)
+	cancel()
+
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	_, open := <-models
+	assert.False(t, open)
+}
@@ -0,0 +1,140 @@
+package odataClient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ODataEntity represents a single entity in the API, like People('russellwhyte') or a single-valued
+// navigation property like People('russellwhyte')/BestFriend
+type ODataEntity[ModelT any] interface {
+	Get(ctx context.Context, filter ODataFilter, options ...RequestOption) (ModelT, error)
+	Update(ctx context.Context, model ModelT, options ...RequestOption) (ModelT, error)
+	Replace(ctx context.Context, model ModelT, options ...RequestOption) (ModelT, error)
+	UpdateChanges(ctx context.Context, original ModelT, modified ModelT, options ...RequestOption) (ModelT, error)
+
+	getClient() *oDataClient
+	getPath() string
+}
+
+type odataEntity[ModelT any] struct {
+	client *oDataClient
+	path   string
+}
+
+func newEntity[ModelT any](client *oDataClient, path string) ODataEntity[ModelT] {
+	return odataEntity[ModelT]{client: client, path: path}
+}
+
+func (entity odataEntity[ModelT]) getClient() *oDataClient {
+	return entity.client
+}
+
+func (entity odataEntity[ModelT]) getPath() string {
+	return entity.path
+}
+
+func (entity odataEntity[ModelT]) getUrl() string {
+	return entity.client.baseUrl + entity.path
+}
+
+// Get the model from the API, the filter can be used to $select and $expand properties
+func (entity odataEntity[ModelT]) Get(ctx context.Context, filter ODataFilter, options ...RequestOption) (ModelT, error) {
+	var responseModel ModelT
+	request, err := http.NewRequestWithContext(ctx, "GET", withQueryString(entity.getUrl(), filter), nil)
+	if err != nil {
+		return responseModel, err
+	}
+	applyRequestOptions(request, options)
+	response, body, err := executeRawHttpRequest(*entity.client, request)
+	if err != nil {
+		return responseModel, err
+	}
+	if responseModel, err = decodeEntity[ModelT](body); err != nil {
+		return responseModel, err
+	}
+	setResponseETag(&responseModel, response)
+	return responseModel, nil
+}
+
+// Update the model in the API with PATCH, so the properties of the model are merged into the stored model.
+// The ETag of the model is sent as If-Match.
+func (entity odataEntity[ModelT]) Update(ctx context.Context, model ModelT, options ...RequestOption) (ModelT, error) {
+	return writeModel(ctx, *entity.client, "PATCH", entity.getUrl(), model, model, withETag(model, options))
+}
+
+// Replace the model in the API with PUT, so properties missing from the model are reset to their default values.
+// The ETag of the model is sent as If-Match.
+func (entity odataEntity[ModelT]) Replace(ctx context.Context, model ModelT, options ...RequestOption) (ModelT, error) {
+	return writeModel(ctx, *entity.client, "PUT", entity.getUrl(), model, model, withETag(model, options))
+}
+
+// UpdateChanges updates the model in the API with PATCH, sending only the properties where the modified
+// model differs from the original model. If nothing has changed, no request is sent. The ETag of the
+// original model is sent as If-Match.
+func (entity odataEntity[ModelT]) UpdateChanges(ctx context.Context, original ModelT, modified ModelT, options ...RequestOption) (ModelT, error) {
+	changes, err := Changes(original, modified)
+	if err != nil {
+		return modified, err
+	}
+	if len(changes) == 0 {
+		return modified, nil
+	}
+	return writeModel(ctx, *entity.client, "PATCH", entity.getUrl(), changes, modified, withETag(original, options))
+}
+
+// decodeEntity decodes a single entity response. Both the entity itself and an entity wrapped in
+// {"value": ...} are supported.
+func decodeEntity[ModelT any](body []byte) (ModelT, error) {
+	properties := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &properties); err == nil {
+		if value, ok := properties["value"]; ok && onlyControlInformation(properties, "value") {
+			return decodeValue[ModelT](value)
+		}
+	}
+	return decodeValue[ModelT](body)
+}
+
+func onlyControlInformation(properties map[string]json.RawMessage, except string) bool {
+	for key := range properties {
+		if key != except && !strings.HasPrefix(key, "@") {
+			return false
+		}
+	}
+	return true
+}
+
+// writeModel sends the payload as JSON, without the navigation properties of the model when updating.
+// The decimals of the model are checked against the Precision and Scale of their properties before sending.
+// When the service responds with 204 No Content, the model is returned.
+func writeModel[ModelT any](ctx context.Context, client oDataClient, method string, requestUrl string, payload interface{}, model ModelT, options []RequestOption) (ModelT, error) {
+	var result ModelT
+	if err := validateFacets(model); err != nil {
+		return result, err
+	}
+	jsonData, err := marshalPayload(payload, writePayloadLeaveOut(method, model)...)
+	if err != nil {
+		return result, err
+	}
+	request, err := http.NewRequestWithContext(ctx, method, requestUrl, bytes.NewReader(jsonData))
+	if err != nil {
+		return result, err
+	}
+	request.Header.Set("Content-Type", "application/json;odata.metadata=minimal")
+	request.Header.Set("Prefer", "return=representation")
+	applyRequestOptions(request, options)
+	response, body, err := executeRawHttpRequest(client, request)
+	if err != nil {
+		return result, err
+	}
+	if response.StatusCode == http.StatusNoContent || len(body) == 0 {
+		result = model
+	} else if result, err = decodeValue[ModelT](body); err != nil {
+		return result, err
+	}
+	setResponseETag(&result, response)
+	return result, nil
+}
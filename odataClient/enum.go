@@ -0,0 +1,137 @@
+package odataClient
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnumValue is the underlying integer type of a generated enum type
+type EnumValue interface {
+	~uint8 | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// EnumMember is a member of an enum type with its name in the service
+type EnumMember[E EnumValue] struct {
+	Value E
+	Name  string
+}
+
+// EnumType holds the members of a generated enum type, and formats and parses its values by the member names,
+// as OData does in JSON and URL literals. Values of flags enums are formatted as comma separated names.
+type EnumType[E EnumValue] struct {
+	qualifiedName string
+	isFlags       bool
+	members       []EnumMember[E]
+}
+
+func NewEnumType[E EnumValue](qualifiedName string, isFlags bool, members ...EnumMember[E]) EnumType[E] {
+	members = append([]EnumMember[E]{}, members...)
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].Value < members[j].Value
+	})
+	return EnumType[E]{qualifiedName: qualifiedName, isFlags: isFlags, members: members}
+}
+
+// QualifiedName is the namespace qualified name of the enum type in the service
+func (t EnumType[E]) QualifiedName() string {
+	return t.qualifiedName
+}
+
+// IsFlags returns true if values can be a combination of members
+func (t EnumType[E]) IsFlags() bool {
+	return t.isFlags
+}
+
+// Values returns the members in the order of their values
+func (t EnumType[E]) Values() []E {
+	values := make([]E, len(t.members))
+	for i, member := range t.members {
+		values[i] = member.Value
+	}
+	return values
+}
+
+// Format returns the name of the member, or for flags enums the names of the flags separated by commas.
+// Values without a matching member are formatted as a number.
+func (t EnumType[E]) Format(value E) string {
+	for _, member := range t.members {
+		if member.Value == value {
+			return member.Name
+		}
+	}
+	if !t.isFlags || value == 0 {
+		return strconv.FormatInt(int64(value), 10)
+	}
+
+	var names []string
+	remaining := value
+	for i := len(t.members) - 1; i >= 0; i-- {
+		member := t.members[i]
+		if member.Value != 0 && remaining&member.Value == member.Value {
+			names = append([]string{member.Name}, names...)
+			remaining &^= member.Value
+		}
+	}
+	if remaining != 0 {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strings.Join(names, ",")
+}
+
+// Parse returns the value of the member name, or for flags enums the combination of comma separated names.
+// Numbers are accepted as well.
+func (t EnumType[E]) Parse(value string) (E, error) {
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return E(number), nil
+	}
+	names := []string{value}
+	if t.isFlags {
+		names = strings.Split(value, ",")
+	}
+	var result E
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, member := range t.members {
+			if member.Name == name {
+				result |= member.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("%q is not a member of %s", name, t.qualifiedName)
+		}
+	}
+	return result, nil
+}
+
+// Literal formats the value as an OData URL literal, e.g. Trippin.PersonGender'Female'
+func (t EnumType[E]) Literal(value E) string {
+	return t.qualifiedName + "'" + t.Format(value) + "'"
+}
+
+// EncodeJSON writes the value as a string with the member names
+func (t EnumType[E]) EncodeJSON(value E) ([]byte, error) {
+	return json.Marshal(t.Format(value))
+}
+
+// DecodeJSON reads the value from a string with the member names, or from a number
+func (t EnumType[E]) DecodeJSON(data []byte, value *E) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		text = string(data)
+	}
+	parsed, err := t.Parse(text)
+	if err != nil {
+		return err
+	}
+	*value = parsed
+	return nil
+}
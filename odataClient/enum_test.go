@@ -0,0 +1,87 @@
+package odataClient
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testPermission uint8
+
+const (
+	permissionNone      testPermission = 0
+	permissionRead      testPermission = 1
+	permissionWrite     testPermission = 2
+	permissionReadWrite testPermission = 3
+	permissionDelete    testPermission = 4
+)
+
+var testPermissionEnum = NewEnumType[testPermission]("Shop.Permission", true,
+	EnumMember[testPermission]{Value: permissionWrite, Name: "Write"},
+	EnumMember[testPermission]{Value: permissionNone, Name: "None"},
+	EnumMember[testPermission]{Value: permissionRead, Name: "Read"},
+	EnumMember[testPermission]{Value: permissionReadWrite, Name: "ReadWrite"},
+	EnumMember[testPermission]{Value: permissionDelete, Name: "Delete"},
+)
+
+func (e testPermission) MarshalJSON() ([]byte, error) {
+	return testPermissionEnum.EncodeJSON(e)
+}
+
+func (e *testPermission) UnmarshalJSON(data []byte) error {
+	return testPermissionEnum.DecodeJSON(data, e)
+}
+
+type testSize int32
+
+var testSizeEnum = NewEnumType[testSize]("Shop.Size", false,
+	EnumMember[testSize]{Value: 0, Name: "Small"},
+	EnumMember[testSize]{Value: 1, Name: "Large"},
+)
+
+func TestEnumType_Format(t *testing.T) {
+	assert.Equal(t, "Large", testSizeEnum.Format(1))
+	assert.Equal(t, "7", testSizeEnum.Format(7))
+	assert.Equal(t, "None", testPermissionEnum.Format(permissionNone))
+	assert.Equal(t, "ReadWrite", testPermissionEnum.Format(permissionRead|permissionWrite))
+	assert.Equal(t, "Read,Delete", testPermissionEnum.Format(permissionRead|permissionDelete))
+	assert.Equal(t, "ReadWrite,Delete", testPermissionEnum.Format(permissionReadWrite|permissionDelete))
+	assert.Equal(t, "9", testPermissionEnum.Format(9))
+	assert.Equal(t, "Shop.Permission'Read,Delete'", testPermissionEnum.Literal(permissionRead|permissionDelete))
+	assert.Equal(t, []testPermission{0, 1, 2, 3, 4}, testPermissionEnum.Values())
+	assert.True(t, testPermissionEnum.IsFlags())
+	assert.Equal(t, "Shop.Size", testSizeEnum.QualifiedName())
+}
+
+func TestEnumType_Parse(t *testing.T) {
+	size, err := testSizeEnum.Parse("Large")
+	assert.NoError(t, err)
+	assert.Equal(t, testSize(1), size)
+	_, err = testSizeEnum.Parse("Small,Large")
+	assert.EqualError(t, err, `"Small,Large" is not a member of Shop.Size`)
+
+	permission, err := testPermissionEnum.Parse("Read, Delete")
+	assert.NoError(t, err)
+	assert.Equal(t, permissionRead|permissionDelete, permission)
+	permission, err = testPermissionEnum.Parse("6")
+	assert.NoError(t, err)
+	assert.Equal(t, permissionWrite|permissionDelete, permission)
+	_, err = testPermissionEnum.Parse("Read,Execute")
+	assert.EqualError(t, err, `"Execute" is not a member of Shop.Permission`)
+}
+
+func TestEnumType_Json(t *testing.T) {
+	type user struct {
+		Permission testPermission `json:"Permission"`
+	}
+	jsonData, err := json.Marshal(user{permissionWrite | permissionDelete})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Permission":"Write,Delete"}`, string(jsonData))
+
+	var data user
+	assert.NoError(t, json.Unmarshal([]byte(`{"Permission":"ReadWrite"}`), &data))
+	assert.Equal(t, permissionReadWrite, data.Permission)
+	assert.NoError(t, json.Unmarshal([]byte(`{"Permission":4}`), &data))
+	assert.Equal(t, permissionDelete, data.Permission)
+	assert.Error(t, json.Unmarshal([]byte(`{"Permission":"Execute"}`), &data))
+}
@@ -0,0 +1,118 @@
+package odataClient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ODataError represents an error response from the OData service
+type ODataError struct {
+	StatusCode int
+	Method     string
+	Url        string
+	Code       string
+	Message    string
+	Target     string
+	Details    []ODataErrorDetail
+	InnerError json.RawMessage
+	// Body is the raw response body, useful when the service did not respond with an OData error
+	Body []byte
+}
+
+// ODataErrorDetail represents an entry of the details in an OData error response
+type ODataErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Target  string `json:"target"`
+}
+
+type apiErrorResponse struct {
+	Error struct {
+		Code       string             `json:"code"`
+		Message    json.RawMessage    `json:"message"`
+		Target     string             `json:"target"`
+		Details    []ODataErrorDetail `json:"details"`
+		InnerError json.RawMessage    `json:"innererror"`
+	} `json:"error"`
+}
+
+func newODataError(request *http.Request, statusCode int, body []byte) *ODataError {
+	oDataError := &ODataError{
+		StatusCode: statusCode,
+		Method:     request.Method,
+		Url:        request.URL.String(),
+		Body:       body,
+	}
+	var response apiErrorResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return oDataError
+	}
+	oDataError.Code = response.Error.Code
+	oDataError.Message = parseErrorMessage(response.Error.Message)
+	oDataError.Target = response.Error.Target
+	oDataError.Details = response.Error.Details
+	oDataError.InnerError = response.Error.InnerError
+	return oDataError
+}
+
+// parseErrorMessage supports both the OData v4 message string and the older {"lang": "", "value": ""} object
+func parseErrorMessage(data json.RawMessage) string {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		return message
+	}
+	var localizedMessage struct {
+		Value string `json:"value"`
+	}
+	_ = json.Unmarshal(data, &localizedMessage)
+	return localizedMessage.Value
+}
+
+func (e *ODataError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	if e.Code != "" {
+		message = e.Code + ": " + message
+	}
+	return fmt.Sprintf("%s %s returned status %d: %s", e.Method, e.Url, e.StatusCode, message)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var oDataError *ODataError
+	return errors.As(err, &oDataError) && oDataError.StatusCode == statusCode
+}
+
+// IsNotFound reports whether the error is an OData error with status 404 Not Found
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsNotModified reports whether the error is an OData error with status 304 Not Modified,
+// as returned when the ETag given with IfNoneMatch still matches
+func IsNotModified(err error) bool {
+	return hasStatusCode(err, http.StatusNotModified)
+}
+
+// IsConflict reports whether the error is an OData error with status 409 Conflict
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsPreconditionFailed reports whether the error is an OData error with status 412 Precondition Failed
+func IsPreconditionFailed(err error) bool {
+	return hasStatusCode(err, http.StatusPreconditionFailed)
+}
+
+// IsUnauthorized reports whether the error is an OData error with status 401 Unauthorized
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether the error is an OData error with status 403 Forbidden
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
@@ -0,0 +1,65 @@
+package odataClient
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewODataError(t *testing.T) {
+	request, _ := http.NewRequest("GET", "http://test.api/People('foo')", nil)
+	body := []byte(`{"error":{"code":"NotFound","message":"Resource not found","target":"People","details":[{"code":"D1","message":"Detail","target":"UserName"}],"innererror":{"trace":"abc"}}}`)
+	err := newODataError(request, 404, body)
+
+	assert.Equal(t, 404, err.StatusCode)
+	assert.Equal(t, "GET", err.Method)
+	assert.Equal(t, "http://test.api/People('foo')", err.Url)
+	assert.Equal(t, "NotFound", err.Code)
+	assert.Equal(t, "Resource not found", err.Message)
+	assert.Equal(t, "People", err.Target)
+	assert.Equal(t, []ODataErrorDetail{{Code: "D1", Message: "Detail", Target: "UserName"}}, err.Details)
+	assert.JSONEq(t, `{"trace":"abc"}`, string(err.InnerError))
+	assert.Equal(t, "GET http://test.api/People('foo') returned status 404: NotFound: Resource not found", err.Error())
+}
+
+func TestNewODataError_withoutODataBody(t *testing.T) {
+	request, _ := http.NewRequest("DELETE", "http://test.api/People(5)", nil)
+	err := newODataError(request, 500, []byte("<html>Internal error</html>"))
+	assert.Equal(t, "", err.Code)
+	assert.Equal(t, "<html>Internal error</html>", string(err.Body))
+	assert.Equal(t, "DELETE http://test.api/People(5) returned status 500: Internal Server Error", err.Error())
+
+	err = newODataError(request, 400, []byte(`{"error":{"code":"","message":{"lang":"en-US","value":"Bad key"}}}`))
+	assert.Equal(t, "Bad key", err.Message)
+}
+
+func TestErrorHelpers(t *testing.T) {
+	request, _ := http.NewRequest("GET", "http://test.api/People", nil)
+	assert.True(t, IsNotFound(fmt.Errorf("wrapped: %w", newODataError(request, 404, nil))))
+	assert.False(t, IsNotFound(newODataError(request, 409, nil)))
+	assert.True(t, IsConflict(newODataError(request, 409, nil)))
+	assert.True(t, IsPreconditionFailed(newODataError(request, 412, nil)))
+	assert.True(t, IsUnauthorized(newODataError(request, 401, nil)))
+	assert.True(t, IsForbidden(newODataError(request, 403, nil)))
+	assert.False(t, IsNotFound(fmt.Errorf("some error")))
+	assert.False(t, IsNotFound(nil))
+}
+
+func TestStatusCodeIsChecked(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(404)
+		_, _ = writer.Write([]byte(`{"error":{"code":"NotFound","message":"No such person"}}`))
+	}))
+	defer testServer.Close()
+
+	dataSet := newTestModelDefinition(New(testServer.URL)).DataSet()
+	_, err := dataSet.Single(context.Background(), Key(5), ODataFilter{})
+	assert.True(t, IsNotFound(err))
+
+	err = dataSet.Delete(context.Background(), Key(5))
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, "No such person", err.(*ODataError).Message)
+}
@@ -0,0 +1,110 @@
+package odataClient
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+)
+
+// EntityMetadata holds the OData control information of an entity. Generated entity models embed it,
+// so the ETag of the entity is captured when it is read, and sent as If-Match when it is updated.
+type EntityMetadata struct {
+	ETag string `json:"@odata.etag,omitempty"`
+}
+
+// ODataETag returns the ETag of the entity
+func (metadata EntityMetadata) ODataETag() string {
+	return metadata.ETag
+}
+
+func (metadata *EntityMetadata) setODataETag(etag string) {
+	metadata.ETag = etag
+}
+
+type etagWriter interface {
+	setODataETag(etag string)
+}
+
+// IfMatch only performs the request when the entity still has the given ETag, otherwise the service
+// responds with 412 Precondition Failed, see IsPreconditionFailed
+func IfMatch(etag string) RequestOption {
+	return WithHeader("If-Match", etag)
+}
+
+// IfNoneMatch only performs the request when the entity no longer has the given ETag, otherwise the
+// service responds with 304 Not Modified, see IsNotModified
+func IfNoneMatch(etag string) RequestOption {
+	return WithHeader("If-None-Match", etag)
+}
+
+func modelETag(model interface{}) string {
+	if reader, ok := model.(interface{ ODataETag() string }); ok {
+		return reader.ODataETag()
+	}
+	return ""
+}
+
+// withETag sends the ETag of the model as If-Match, unless the options set another precondition
+func withETag(model interface{}, options []RequestOption) []RequestOption {
+	etag := modelETag(model)
+	if etag == "" {
+		return options
+	}
+	return append([]RequestOption{IfMatch(etag)}, options...)
+}
+
+// setResponseETag stores the ETag header of the response on the model. When ModelT is an interface like AnyPerson,
+// the ETag is stored on the value it holds.
+func setResponseETag[ModelT any](model *ModelT, response *http.Response) {
+	etag := response.Header.Get("ETag")
+	if etag == "" {
+		return
+	}
+	if writer, ok := interface{}(model).(etagWriter); ok {
+		writer.setODataETag(etag)
+		return
+	}
+	value := reflect.ValueOf(model).Elem()
+	if value.Kind() != reflect.Interface || value.IsNil() {
+		return
+	}
+	if writer, ok := value.Elem().Interface().(etagWriter); ok {
+		writer.setODataETag(etag)
+		return
+	}
+	// the value held by the interface can't be changed, so a copy with the ETag replaces it
+	dynamicValue := reflect.New(value.Elem().Type())
+	dynamicValue.Elem().Set(value.Elem())
+	if writer, ok := dynamicValue.Interface().(etagWriter); ok {
+		writer.setODataETag(etag)
+		value.Set(dynamicValue.Elem())
+	}
+}
+
+// marshalPayload marshals the model to JSON, without the control information which is only
+// meant for responses, and without the properties to leave out
+func marshalPayload(payload interface{}, leaveOut ...string) ([]byte, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil || (len(leaveOut) == 0 && !bytes.Contains(jsonData, []byte(`"@odata.etag"`))) {
+		return jsonData, err
+	}
+	properties := map[string]json.RawMessage{}
+	if err = json.Unmarshal(jsonData, &properties); err != nil {
+		return jsonData, nil
+	}
+	delete(properties, "@odata.etag")
+	for _, property := range leaveOut {
+		delete(properties, property)
+	}
+	return json.Marshal(properties)
+}
+
+// writePayloadLeaveOut returns the properties which are not sent with the method. Navigation properties are only
+// sent when inserting, as deep insert, since most services reject them in updates.
+func writePayloadLeaveOut(method string, model interface{}) []string {
+	if method != "PATCH" && method != "PUT" {
+		return nil
+	}
+	return navigationProperties(model)
+}
@@ -0,0 +1,154 @@
+package odataClient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testETagModel struct {
+	EntityMetadata
+	Id   int
+	Name string
+}
+
+func newTestETagDataSet(client ODataClient) ODataDataSet[testETagModel, ODataModelDefinition[testETagModel]] {
+	return testModelDefinition[testETagModel]{client: client}.DataSet()
+}
+
+type testAnyETagModel interface {
+	AsTestETagModel() testETagModel
+}
+
+func (m testETagModel) AsTestETagModel() testETagModel {
+	return m
+}
+
+type testDerivedETagModel struct {
+	testETagModel
+	Level int
+}
+
+func init() {
+	RegisterType[testETagModel]("Test.ETagModel")
+	RegisterType[testDerivedETagModel]("Test.DerivedETagModel")
+	RegisterFallbackType[testAnyETagModel, testETagModel]()
+}
+
+func TestMarshalPayload(t *testing.T) {
+	jsonData, err := marshalPayload(testETagModel{EntityMetadata{ETag: `W/"1"`}, 5, "Foo"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Id":5,"Name":"Foo"}`, string(jsonData))
+}
+
+func TestETag_List(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`{"value":[{"@odata.etag":"W/\"1\"","Id":1},{"@odata.etag":"W/\"2\"","Id":2}]}`))
+	}))
+	defer testServer.Close()
+
+	models, _ := newTestETagDataSet(New(testServer.URL)).List(context.Background(), ODataFilter{})
+	var etags []string
+	for model := range models {
+		etags = append(etags, model.ETag)
+	}
+	assert.Equal(t, []string{`W/"1"`, `W/"2"`}, etags)
+}
+
+func TestETag_SingleAndUpdate(t *testing.T) {
+	var ifMatch string
+	var body map[string]interface{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case "GET":
+			if request.Header.Get("If-None-Match") == `W/"1"` {
+				writer.WriteHeader(304)
+				return
+			}
+			writer.Header().Set("ETag", `W/"1"`)
+			_, _ = writer.Write([]byte(`{"value":{"Id":5,"Name":"Foo"}}`))
+		case "PATCH":
+			ifMatch = request.Header.Get("If-Match")
+			data, _ := ioutil.ReadAll(request.Body)
+			_ = json.Unmarshal(data, &body)
+			if ifMatch != `W/"1"` {
+				writer.WriteHeader(412)
+				return
+			}
+			writer.Header().Set("ETag", `W/"2"`)
+			writer.WriteHeader(204)
+		}
+	}))
+	defer testServer.Close()
+
+	dataSet := newTestETagDataSet(New(testServer.URL))
+	model, err := dataSet.Single(context.Background(), Key(5), ODataFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, `W/"1"`, model.ETag)
+
+	_, err = dataSet.Single(context.Background(), Key(5), ODataFilter{}, IfNoneMatch(model.ETag))
+	assert.True(t, IsNotModified(err))
+
+	model.Name = "Bar"
+	updated, err := dataSet.Update(context.Background(), Key(5), model)
+	assert.NoError(t, err)
+	assert.Equal(t, `W/"1"`, ifMatch)
+	assert.Equal(t, map[string]interface{}{"Id": float64(5), "Name": "Bar"}, body)
+	assert.Equal(t, `W/"2"`, updated.ETag)
+
+	_, err = dataSet.Update(context.Background(), Key(5), updated)
+	assert.True(t, IsPreconditionFailed(err))
+}
+
+func TestETag_Delete(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("If-Match") != `W/"1"` {
+			writer.WriteHeader(412)
+			return
+		}
+		writer.WriteHeader(204)
+	}))
+	defer testServer.Close()
+
+	dataSet := newTestETagDataSet(New(testServer.URL))
+	assert.NoError(t, dataSet.Delete(context.Background(), Key(5), IfMatch(`W/"1"`)))
+	assert.True(t, IsPreconditionFailed(dataSet.Delete(context.Background(), Key(5), IfMatch(`W/"2"`))))
+
+	model := testETagModel{Id: 5}
+	model.ETag = `W/"1"`
+	assert.NoError(t, dataSet.DeleteModel(context.Background(), Key(5), model))
+	model.ETag = `W/"2"`
+	assert.True(t, IsPreconditionFailed(dataSet.DeleteModel(context.Background(), Key(5), model)))
+}
+
+func TestETag_derivedType(t *testing.T) {
+	var ifMatch string
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method {
+		case "GET":
+			writer.Header().Set("ETag", `W/"1"`)
+			_, _ = writer.Write([]byte(`{"@odata.type":"#Test.DerivedETagModel","Id":5,"Name":"Foo","Level":2}`))
+		case "PATCH":
+			ifMatch = request.Header.Get("If-Match")
+			writer.Header().Set("ETag", `W/"2"`)
+			writer.WriteHeader(204)
+		}
+	}))
+	defer testServer.Close()
+
+	dataSet := testModelDefinition[testAnyETagModel]{client: New(testServer.URL)}.DataSet()
+	model, err := dataSet.Single(context.Background(), Key(5), ODataFilter{})
+	assert.NoError(t, err)
+	assert.IsType(t, testDerivedETagModel{}, model)
+	assert.Equal(t, `W/"1"`, model.AsTestETagModel().ETag)
+
+	updated, err := dataSet.Update(context.Background(), Key(5), model)
+	assert.NoError(t, err)
+	assert.Equal(t, `W/"1"`, ifMatch)
+	assert.IsType(t, testDerivedETagModel{}, updated)
+	assert.Equal(t, `W/"2"`, updated.AsTestETagModel().ETag)
+}
@@ -0,0 +1,121 @@
+package odataClient
+
+import (
+	"fmt"
+	"github.com/Uffe-Code/go-odata/decimal"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var decimalType = reflect.TypeOf(decimal.Decimal{})
+
+// decimalFacets are the Precision and Scale of an Edm.Decimal property, which the generator adds to the field as
+// odata:"precision=18,scale=2". Facets which are missing, or not a number like Scale="variable", are not checked.
+type decimalFacets struct {
+	precision *int
+	scale     *int32
+}
+
+func parseDecimalFacets(tag string) decimalFacets {
+	var facets decimalFacets
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		number, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "precision":
+			precision := int(number)
+			facets.precision = &precision
+		case "scale":
+			scale := int32(number)
+			facets.scale = &scale
+		}
+	}
+	return facets
+}
+
+func (f decimalFacets) fits(value decimal.Decimal) bool {
+	scale := value.Scale()
+	if f.scale != nil {
+		scale = *f.scale
+	}
+	if f.precision == nil {
+		return value.Round(scale).Cmp(value) == 0
+	}
+	return value.Fits(*f.precision, scale)
+}
+
+func (f decimalFacets) String() string {
+	var facets []string
+	if f.precision != nil {
+		facets = append(facets, fmt.Sprintf("Precision %d", *f.precision))
+	}
+	if f.scale != nil {
+		facets = append(facets, fmt.Sprintf("Scale %d", *f.scale))
+	}
+	return strings.Join(facets, ", ")
+}
+
+// validateFacets returns an error when a decimal of the model does not fit the Precision and Scale of its property,
+// since services either reject or silently round such values
+func validateFacets(model interface{}) error {
+	return validateValueFacets(reflect.ValueOf(model), decimalFacets{})
+}
+
+func validateValueFacets(value reflect.Value, facets decimalFacets) error {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return validateValueFacets(value.Elem(), facets)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := validateValueFacets(value.Index(i), facets); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		switch {
+		case value.Type() == decimalType:
+			if d := value.Interface().(decimal.Decimal); !facets.fits(d) {
+				return fmt.Errorf("%s does not fit %s", d, facets)
+			}
+		case isNullableType(value.Type()):
+			if value.FieldByName("IsValid").Bool() {
+				return validateValueFacets(value.FieldByName("Data"), facets)
+			}
+		default:
+			return validateFieldFacets(value)
+		}
+	}
+	return nil
+}
+
+// validateFieldFacets validates the fields of the struct, and the fields of its embedded structs
+func validateFieldFacets(model reflect.Value) error {
+	modelType := model.Type()
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			if err := validateValueFacets(model.Field(i), decimalFacets{}); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if err := validateValueFacets(model.Field(i), parseDecimalFacets(field.Tag.Get("odata"))); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
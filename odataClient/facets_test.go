@@ -0,0 +1,75 @@
+package odataClient
+
+import (
+	"context"
+	"github.com/Uffe-Code/go-nullable/nullable"
+	"github.com/Uffe-Code/go-odata/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testFacetsLine struct {
+	Amount decimal.Decimal `json:"Amount" odata:"precision=5,scale=2"`
+}
+
+type testFacetsOrder struct {
+	Id       int                                `json:"Id"`
+	Total    decimal.Decimal                    `json:"Total" odata:"precision=5,scale=2"`
+	Discount nullable.Nullable[decimal.Decimal] `json:"Discount" odata:"scale=1"`
+	Rate     *decimal.Decimal                   `json:"Rate" odata:"precision=3,scale=variable"`
+	Lines    []testFacetsLine                   `json:"Lines"`
+	Free     decimal.Decimal                    `json:"Free"`
+}
+
+func TestValidateFacets(t *testing.T) {
+	rate := decimal.MustParse("12.5")
+	order := testFacetsOrder{
+		Total:    decimal.MustParse("999.9"),
+		Discount: nullable.Value(decimal.MustParse("1.50")),
+		Rate:     &rate,
+		Lines:    []testFacetsLine{{Amount: decimal.MustParse("-12.34")}},
+		Free:     decimal.MustParse("123456789.123456789"),
+	}
+	assert.NoError(t, validateFacets(order))
+	assert.NoError(t, validateFacets(&order))
+
+	tooLarge := order
+	tooLarge.Total = decimal.MustParse("1000")
+	assert.EqualError(t, validateFacets(tooLarge), "Total: 1000 does not fit Precision 5, Scale 2")
+
+	tooPrecise := order
+	tooPrecise.Discount = nullable.Value(decimal.MustParse("1.55"))
+	assert.EqualError(t, validateFacets(tooPrecise), "Discount: 1.55 does not fit Scale 1")
+
+	tooManyDigits := order
+	tooManyDigits.Rate = new(decimal.Decimal)
+	*tooManyDigits.Rate = decimal.MustParse("1.234")
+	assert.EqualError(t, validateFacets(tooManyDigits), "Rate: 1.234 does not fit Precision 3")
+
+	line := order
+	line.Lines = []testFacetsLine{{Amount: decimal.MustParse("0.001")}}
+	assert.EqualError(t, validateFacets(line), "Lines: Amount: 0.001 does not fit Precision 5, Scale 2")
+}
+
+func TestValidateFacets_insert(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests++
+		writer.WriteHeader(204)
+	}))
+	defer testServer.Close()
+
+	dataSet := testModelDefinition[testFacetsOrder]{client: New(testServer.URL)}.DataSet()
+	_, err := dataSet.Insert(context.Background(), testFacetsOrder{Total: decimal.MustParse("12.345")})
+	assert.EqualError(t, err, "Total: 12.345 does not fit Precision 5, Scale 2")
+	_, err = dataSet.Update(context.Background(), Key(1), testFacetsOrder{Total: decimal.MustParse("12.34")})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	batch := NewBatch(New(testServer.URL), BatchFormatJSON)
+	result := BatchInsert(batch, dataSet, testFacetsOrder{Total: decimal.MustParse("12.345")})
+	assert.EqualError(t, result.err, "Total: 12.345 does not fit Precision 5, Scale 2")
+	assert.Empty(t, batch.operations())
+}
@@ -0,0 +1,262 @@
+package odataClient
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/Uffe-Code/go-odata/date"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const nullablePackagePath = "github.com/Uffe-Code/go-nullable/nullable"
+
+// FilterExpression represents a part of an OData $filter expression
+type FilterExpression interface {
+	String() string
+}
+
+// Literal is implemented by types that know how to render themselves as an OData URL literal,
+// for example generated enum types
+type Literal interface {
+	ODataLiteral() string
+}
+
+type rawExpression string
+
+func (e rawExpression) String() string {
+	return string(e)
+}
+
+type comparisonExpression struct {
+	property string
+	operator string
+	value    interface{}
+}
+
+func (e comparisonExpression) String() string {
+	return fmt.Sprintf("%s %s %s", e.property, e.operator, formatLiteral(e.value))
+}
+
+type inExpression struct {
+	property string
+	values   []interface{}
+}
+
+func (e inExpression) String() string {
+	if len(e.values) == 0 {
+		return "false"
+	}
+	literals := make([]string, len(e.values))
+	for i, value := range e.values {
+		literals[i] = formatLiteral(value)
+	}
+	return fmt.Sprintf("%s in (%s)", e.property, strings.Join(literals, ","))
+}
+
+type logicalExpression struct {
+	operator    string
+	expressions []FilterExpression
+}
+
+// String renders an And without expressions as true and an Or without expressions as false, like an empty In
+func (e logicalExpression) String() string {
+	parts := make([]string, 0, len(e.expressions))
+	for _, expression := range e.expressions {
+		if expression == nil {
+			continue
+		}
+		if inner, ok := expression.(logicalExpression); ok && inner.operator != e.operator && len(inner.nonNil()) > 1 {
+			parts = append(parts, "("+inner.String()+")")
+			continue
+		}
+		parts = append(parts, expression.String())
+	}
+	if len(parts) == 0 {
+		return strconv.FormatBool(e.operator == "and")
+	}
+	return strings.Join(parts, " "+e.operator+" ")
+}
+
+func (e logicalExpression) nonNil() []FilterExpression {
+	var expressions []FilterExpression
+	for _, expression := range e.expressions {
+		if expression != nil {
+			expressions = append(expressions, expression)
+		}
+	}
+	return expressions
+}
+
+type notExpression struct {
+	expression FilterExpression
+}
+
+func (e notExpression) String() string {
+	return "not (" + e.expression.String() + ")"
+}
+
+type groupExpression struct {
+	expression FilterExpression
+}
+
+func (e groupExpression) String() string {
+	return "(" + e.expression.String() + ")"
+}
+
+// Raw uses the given string as a filter expression without any escaping
+func Raw(expression string) FilterExpression {
+	return rawExpression(expression)
+}
+
+// Eq matches when the property equals the value
+func Eq(property string, value interface{}) FilterExpression {
+	return comparisonExpression{property: property, operator: "eq", value: value}
+}
+
+// Ne matches when the property does not equal the value
+func Ne(property string, value interface{}) FilterExpression {
+	return comparisonExpression{property: property, operator: "ne", value: value}
+}
+
+// Gt matches when the property is greater than the value
+func Gt(property string, value interface{}) FilterExpression {
+	return comparisonExpression{property: property, operator: "gt", value: value}
+}
+
+// Ge matches when the property is greater than or equal to the value
+func Ge(property string, value interface{}) FilterExpression {
+	return comparisonExpression{property: property, operator: "ge", value: value}
+}
+
+// Lt matches when the property is less than the value
+func Lt(property string, value interface{}) FilterExpression {
+	return comparisonExpression{property: property, operator: "lt", value: value}
+}
+
+// Le matches when the property is less than or equal to the value
+func Le(property string, value interface{}) FilterExpression {
+	return comparisonExpression{property: property, operator: "le", value: value}
+}
+
+// Has matches when the enum property has the given flag set
+func Has(property string, value interface{}) FilterExpression {
+	return comparisonExpression{property: property, operator: "has", value: value}
+}
+
+// In matches when the property equals one of the values, without values it matches nothing
+func In(property string, values ...interface{}) FilterExpression {
+	return inExpression{property: property, values: values}
+}
+
+// GeoDistance is the distance between the spatial property and the point, to be compared with Lt or Le, e.g.
+// Lt(GeoDistance("Location", geo.NewGeographyPoint(4.9, 52.4)), 1000) for locations within a kilometer
+func GeoDistance(property string, point interface{}) string {
+	return fmt.Sprintf("geo.distance(%s,%s)", property, formatLiteral(point))
+}
+
+// GeoLength is the length of the line string property
+func GeoLength(property string) string {
+	return fmt.Sprintf("geo.length(%s)", property)
+}
+
+// GeoIntersects matches when the spatial property, e.g. a point, lies within the polygon
+func GeoIntersects(property string, polygon interface{}) FilterExpression {
+	return rawExpression(fmt.Sprintf("geo.intersects(%s,%s)", property, formatLiteral(polygon)))
+}
+
+// And matches when all the expressions match
+func And(expressions ...FilterExpression) FilterExpression {
+	return logicalExpression{operator: "and", expressions: expressions}
+}
+
+// Or matches when any of the expressions match
+func Or(expressions ...FilterExpression) FilterExpression {
+	return logicalExpression{operator: "or", expressions: expressions}
+}
+
+// Not negates the expression, a nil expression is left out like in And and Or
+func Not(expression FilterExpression) FilterExpression {
+	if expression == nil {
+		return nil
+	}
+	return notExpression{expression: expression}
+}
+
+// Group wraps the expression in parentheses, a nil expression is left out like in And and Or
+func Group(expression FilterExpression) FilterExpression {
+	if expression == nil {
+		return nil
+	}
+	return groupExpression{expression: expression}
+}
+
+// formatLiteral renders a Go value as an OData v4 URL literal
+func formatLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case Literal:
+		return v.ODataLiteral()
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case date.Date:
+		return v.String()
+	case []byte:
+		return "binary'" + base64.URLEncoding.EncodeToString(v) + "'"
+	case float32:
+		return formatFloat(float64(v), 32)
+	case float64:
+		return formatFloat(v, 64)
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Pointer:
+		if reflectValue.IsNil() {
+			return "null"
+		}
+		return formatLiteral(reflectValue.Elem().Interface())
+	case reflect.String:
+		return formatLiteral(reflectValue.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(reflectValue.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(reflectValue.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(reflectValue.Float(), reflectValue.Type().Bits())
+	case reflect.Bool:
+		return formatLiteral(reflectValue.Bool())
+	case reflect.Struct:
+		if isNullableType(reflectValue.Type()) {
+			if !reflectValue.FieldByName("IsValid").Bool() {
+				return "null"
+			}
+			return formatLiteral(reflectValue.FieldByName("Data").Interface())
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// formatFloat formats the float as an OData literal, where the infinities are INF and -INF
+func formatFloat(value float64, bitSize int) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "INF"
+	case math.IsInf(value, -1):
+		return "-INF"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, bitSize)
+}
+
+func isNullableType(t reflect.Type) bool {
+	return t.PkgPath() == nullablePackagePath && strings.HasPrefix(t.Name(), "Nullable[")
+}
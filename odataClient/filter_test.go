@@ -0,0 +1,82 @@
+package odataClient
+
+import (
+	"github.com/Uffe-Code/go-nullable/nullable"
+	"github.com/Uffe-Code/go-odata/date"
+	"github.com/Uffe-Code/go-odata/decimal"
+	"github.com/Uffe-Code/go-odata/geo"
+	"github.com/Uffe-Code/go-odata/uuid"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+type testGender int64
+
+func (g testGender) ODataLiteral() string {
+	return "Trippin.PersonGender'Female'"
+}
+
+func TestFormatLiteral(t *testing.T) {
+	name := "Foo"
+	assert.Equal(t, "null", formatLiteral(nil))
+	assert.Equal(t, "'O''Brien'", formatLiteral("O'Brien"))
+	assert.Equal(t, "'Foo'", formatLiteral(&name))
+	assert.Equal(t, "42", formatLiteral(int32(42)))
+	assert.Equal(t, "-7", formatLiteral(int64(-7)))
+	assert.Equal(t, "1.5", formatLiteral(1.5))
+	assert.Equal(t, "0.1", formatLiteral(float32(0.1)))
+	assert.Equal(t, "NaN", formatLiteral(math.NaN()))
+	assert.Equal(t, "INF", formatLiteral(float32(math.Inf(1))))
+	assert.Equal(t, "-INF", formatLiteral(nullable.Value(math.Inf(-1))))
+	assert.Equal(t, "true", formatLiteral(true))
+	assert.Equal(t, "2021-10-11T08:30:00Z", formatLiteral(time.Date(2021, 10, 11, 8, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "2021-10-11", formatLiteral(date.New(2021, 10, 11)))
+	assert.Equal(t, "Trippin.PersonGender'Female'", formatLiteral(testGender(1)))
+	assert.Equal(t, "null", formatLiteral(nullable.Null[string]()))
+	assert.Equal(t, "'Bar'", formatLiteral(nullable.Value("Bar")))
+	assert.Equal(t, "Trippin.PersonGender'Female'", formatLiteral(nullable.Value(testGender(1))))
+	assert.Equal(t, "binary'-_8='", formatLiteral([]byte{0xfb, 0xff}))
+	assert.Equal(t, "01234567-89ab-cdef-0123-456789abcdef", formatLiteral(uuid.MustParse("01234567-89ab-cdef-0123-456789abcdef")))
+	assert.Equal(t, "duration'PT1H30M'", formatLiteral(date.Duration(90*time.Minute)))
+	assert.Equal(t, "08:30:00", formatLiteral(date.NewTimeOfDay(8, 30, 0, 0)))
+	assert.Equal(t, "79228162514264337593543950335.01", formatLiteral(nullable.Value(decimal.MustParse("79228162514264337593543950335.01"))))
+}
+
+func TestFilterExpressions(t *testing.T) {
+	assert.Equal(t, "Name eq 'O''Brien'", Eq("Name", "O'Brien").String())
+	assert.Equal(t, "Age ne 5", Ne("Age", 5).String())
+	assert.Equal(t, "Age gt 5 and Age le 10", And(Gt("Age", 5), Le("Age", 10)).String())
+	assert.Equal(t, "Age ge 5 or Age lt 1", Or(Ge("Age", 5), Lt("Age", 1)).String())
+	assert.Equal(t, "Name in ('A','B')", In("Name", "A", "B").String())
+	assert.Equal(t, "Gender has Trippin.PersonGender'Female'", Has("Gender", testGender(1)).String())
+	assert.Equal(t, "not (City/Name eq 'Oslo')", Not(Eq("City/Name", "Oslo")).String())
+	assert.Equal(t,
+		"Name eq 'A' and (Age lt 5 or Age gt 10)",
+		And(Eq("Name", "A"), Or(Lt("Age", 5), Gt("Age", 10))).String(),
+	)
+	assert.Equal(t, "(Name eq 'A')", Group(Eq("Name", "A")).String())
+	assert.Equal(t, "false", In("Name").String())
+	assert.Equal(t, "Age gt 5 and false", And(Gt("Age", 5), In("Name", []interface{}{}...)).String())
+	assert.Equal(t, "Age gt 5 and true", And(Gt("Age", 5), And()).String())
+	assert.Equal(t, "Age gt 5 and false", And(Gt("Age", 5), Or(nil)).String())
+	assert.Equal(t, "Age gt 5 or true", Or(Gt("Age", 5), And()).String())
+	assert.Equal(t, "Name eq 'A' or Age gt 5", Or(Eq("Name", "A"), And(Gt("Age", 5), nil)).String())
+	assert.Equal(t, "true", And().String())
+	assert.Equal(t, "false", Or().String())
+	assert.Nil(t, Not(nil))
+	assert.Nil(t, Group(nil))
+	assert.Equal(t, "Age gt 5", And(Gt("Age", 5), Not(nil)).String())
+}
+
+func TestGeoFilterExpressions(t *testing.T) {
+	point := geo.NewGeographyPoint(-122.131577, 47.678581)
+	assert.Equal(t, "geo.distance(Location,geography'SRID=4326;POINT(-122.131577 47.678581)') lt 1000",
+		Lt(GeoDistance("Location", point), 1000).String())
+	assert.Equal(t, "geo.length(Route) gt 5", Gt(GeoLength("Route"), 5).String())
+
+	area := geo.Polygon[geo.Geography]{Rings: [][]geo.Position{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}}
+	assert.Equal(t, "geo.intersects(Location,geography'SRID=4326;POLYGON((0 0,1 0,1 1,0 0))') and Name eq 'A'",
+		And(GeoIntersects("Location", area), Eq("Name", "A")).String())
+}
@@ -0,0 +1,67 @@
+package odataClient
+
+import (
+	"net/url"
+	"strings"
+)
+
+// EntityKey represents the key of an entity, which is formatted as the key predicate of the entity URL
+type EntityKey struct {
+	properties []KeyProperty
+	raw        string
+}
+
+// KeyProperty represents one of the properties of a composite key
+type KeyProperty struct {
+	Name  string
+	Value interface{}
+}
+
+// Key creates the key of an entity with a single key property. The value is formatted as an OData
+// literal, so strings are quoted and escaped.
+func Key(value interface{}) EntityKey {
+	return EntityKey{properties: []KeyProperty{{Value: value}}}
+}
+
+// CompositeKey creates the key of an entity with multiple key properties
+func CompositeKey(properties ...KeyProperty) EntityKey {
+	return EntityKey{properties: properties}
+}
+
+// RawKey uses an already formatted key predicate, for example "OrderID=1,ProductID=2"
+func RawKey(predicate string) EntityKey {
+	return EntityKey{raw: predicate}
+}
+
+// String returns the key predicate, without the surrounding parentheses
+func (key EntityKey) String() string {
+	if key.raw != "" {
+		return key.raw
+	}
+	if len(key.properties) == 1 && key.properties[0].Name == "" {
+		return escapeKeyLiteral(formatLiteral(key.properties[0].Value))
+	}
+	parts := make([]string, len(key.properties))
+	for i, property := range key.properties {
+		parts[i] = property.Name + "=" + escapeKeyLiteral(formatLiteral(property.Value))
+	}
+	return strings.Join(parts, ",")
+}
+
+// segment returns the key as a URL segment to append to the collection URL. When key-as-segment
+// is used, single keys are added as their own segment without quotes, e.g. People/russellwhyte
+func (key EntityKey) segment(keyAsSegment bool) string {
+	if keyAsSegment && key.raw == "" && len(key.properties) == 1 {
+		value := key.properties[0].Value
+		if str, ok := value.(string); ok {
+			return "/" + url.PathEscape(str)
+		}
+		return "/" + escapeKeyLiteral(formatLiteral(value))
+	}
+	return "(" + key.String() + ")"
+}
+
+// escapeKeyLiteral escapes the literal for use in a URL path, keeping the quotes of string literals readable
+func escapeKeyLiteral(literal string) string {
+	return strings.ReplaceAll(url.PathEscape(literal), "%27", "'")
+}
@@ -0,0 +1,30 @@
+package odataClient
+
+import (
+	"github.com/Uffe-Code/go-odata/date"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEntityKey_String(t *testing.T) {
+	assert.Equal(t, "5", Key(5).String())
+	assert.Equal(t, "'russellwhyte'", Key("russellwhyte").String())
+	assert.Equal(t, "'O''Brien%2FJr'", Key("O'Brien/Jr").String())
+	assert.Equal(t, "2021-10-11", Key(date.New(2021, 10, 11)).String())
+	assert.Equal(t, "OrderID=1,ProductID='A%20B'", CompositeKey(
+		KeyProperty{Name: "OrderID", Value: 1},
+		KeyProperty{Name: "ProductID", Value: "A B"},
+	).String())
+	assert.Equal(t, "OrderID=1,ProductID=2", RawKey("OrderID=1,ProductID=2").String())
+}
+
+func TestEntityKey_segment(t *testing.T) {
+	assert.Equal(t, "('russellwhyte')", Key("russellwhyte").segment(false))
+	assert.Equal(t, "/russellwhyte", Key("russellwhyte").segment(true))
+	assert.Equal(t, "/a%2Fb", Key("a/b").segment(true))
+	assert.Equal(t, "/5", Key(5).segment(true))
+	assert.Equal(t, "(OrderID=1,ProductID=2)", CompositeKey(
+		KeyProperty{Name: "OrderID", Value: 1},
+		KeyProperty{Name: "ProductID", Value: 2},
+	).segment(true))
+}
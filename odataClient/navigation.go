@@ -0,0 +1,83 @@
+package odataClient
+
+import (
+	"reflect"
+	"strings"
+)
+
+// pathDefinition is the model definition of data sets which are not entity sets, like navigation properties
+type pathDefinition[T any] struct {
+	name string
+	url  string
+}
+
+func (definition pathDefinition[T]) Name() string {
+	return definition.name
+}
+
+func (definition pathDefinition[T]) Url() string {
+	return definition.url
+}
+
+// NavigationSource is what navigation properties, type casts and operations are applied to, like an ODataEntity,
+// a SingletonSet or an ODataDataSet. ServiceRoot is the source of unbound operations.
+type NavigationSource interface {
+	getClient() *oDataClient
+	getPath() string
+}
+
+// Navigate returns a data set of the collection-valued navigation property of the entity,
+// for example the Trips of People('russellwhyte')
+func Navigate[ChildT any](entity NavigationSource, property string) ODataDataSet[ChildT, ODataModelDefinition[ChildT]] {
+	definition := pathDefinition[ChildT]{name: property, url: entity.getPath() + "/" + property}
+	return NewDataSet[ChildT, ODataModelDefinition[ChildT]](entity.getClient(), definition)
+}
+
+// NavigateSingle returns the single-valued navigation property of the entity,
+// for example the BestFriend of People('russellwhyte')
+func NavigateSingle[ChildT any](entity NavigationSource, property string) ODataEntity[ChildT] {
+	return newEntity[ChildT](entity.getClient(), entity.getPath()+"/"+property)
+}
+
+// TypeCast returns the data set limited to the derived type, for example People/Trippin.Employee.
+// The type name must be namespace qualified.
+func TypeCast[DerivedT any](source NavigationSource, qualifiedTypeName string) ODataDataSet[DerivedT, ODataModelDefinition[DerivedT]] {
+	definition := pathDefinition[DerivedT]{name: qualifiedTypeName, url: source.getPath() + "/" + qualifiedTypeName}
+	return NewDataSet[DerivedT, ODataModelDefinition[DerivedT]](source.getClient(), definition)
+}
+
+// TypeCastSingle returns the entity as the derived type, for example People('russellwhyte')/Trippin.Employee.
+// The type name must be namespace qualified.
+func TypeCastSingle[DerivedT any](entity NavigationSource, qualifiedTypeName string) ODataEntity[DerivedT] {
+	return newEntity[DerivedT](entity.getClient(), entity.getPath()+"/"+qualifiedTypeName)
+}
+
+// navigationProperties returns the JSON names of the fields of the model which are tagged odata:"navigation"
+// by the generator, including the fields of embedded base types
+func navigationProperties(model interface{}) []string {
+	return navigationFields(reflect.TypeOf(model))
+}
+
+func navigationFields(modelType reflect.Type) []string {
+	for modelType != nil && modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return nil
+	}
+	var properties []string
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case field.Tag.Get("odata") == "navigation":
+			if jsonName == "" {
+				jsonName = field.Name
+			}
+			properties = append(properties, jsonName)
+		case field.Anonymous && jsonName == "":
+			properties = append(properties, navigationFields(field.Type)...)
+		}
+	}
+	return properties
+}
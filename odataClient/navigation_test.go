@@ -0,0 +1,123 @@
+package odataClient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testTrip struct {
+	TripId int
+	Name   string
+}
+
+func TestNavigate(t *testing.T) {
+	client := New("http://test.api/")
+	people := newTestModelDefinition(client).DataSet()
+	trips := Navigate[testTrip](people.Entity(Key("russellwhyte")), "Trips")
+	assert.Equal(t, "http://test.api/People('russellwhyte')/Trips", trips.getCollectionUrl())
+	assert.Equal(t, "http://test.api/People('russellwhyte')/Trips(5)", trips.getSingleUrl(Key(5)))
+
+	planItems := Navigate[testModel](trips.Entity(Key(5)), "PlanItems")
+	assert.Equal(t, "http://test.api/People('russellwhyte')/Trips(5)/PlanItems", planItems.getCollectionUrl())
+
+	bestFriend := NavigateSingle[testModel](people.Entity(Key("russellwhyte")), "BestFriend")
+	friendsOfBestFriend := Navigate[testModel](bestFriend, "Friends")
+	assert.Equal(t, "http://test.api/People('russellwhyte')/BestFriend/Friends", friendsOfBestFriend.getCollectionUrl())
+}
+
+func TestNavigate_requests(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/People('russellwhyte')/Trips":
+			_, _ = writer.Write([]byte(`{"value":[{"TripId":1,"Name":"Trip 1"},{"TripId":2,"Name":"Trip 2"}]}`))
+		case "/People('russellwhyte')/BestFriend":
+			_, _ = writer.Write([]byte(`{"@odata.context":"$metadata#People/$entity","Id":3,"Name":"Scott"}`))
+		default:
+			writer.WriteHeader(404)
+		}
+	}))
+	defer testServer.Close()
+
+	people := newTestModelDefinition(New(testServer.URL)).DataSet()
+	trips, errs := Navigate[testTrip](people.Entity(Key("russellwhyte")), "Trips").List(context.Background(), ODataFilter{})
+	var names []string
+	for trip := range trips {
+		names = append(names, trip.Name)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []string{"Trip 1", "Trip 2"}, names)
+
+	bestFriend, err := NavigateSingle[testModel](people.Entity(Key("russellwhyte")), "BestFriend").Get(context.Background(), ODataFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Scott", bestFriend.Name)
+}
+
+func TestTypeCast(t *testing.T) {
+	client := New("http://test.api/")
+	people := newTestModelDefinition(client).DataSet()
+	employees := TypeCast[testModel](people, "Trippin.Employee")
+	assert.Equal(t, "http://test.api/People/Trippin.Employee", employees.getCollectionUrl())
+	assert.Equal(t, "http://test.api/People/Trippin.Employee('russellwhyte')", employees.getSingleUrl(Key("russellwhyte")))
+
+	employee := TypeCastSingle[testModel](people.Entity(Key("russellwhyte")), "Trippin.Employee")
+	assert.Equal(t, "People('russellwhyte')/Trippin.Employee", employee.getPath())
+	assert.Equal(t, "People('russellwhyte')/Trippin.Employee/Peers", Navigate[testModel](employee, "Peers").getPath())
+}
+
+type testExpandedPerson struct {
+	EntityMetadata
+	Id         int
+	Name       string
+	BestFriend *testExpandedPerson `json:"BestFriend,omitempty" odata:"navigation"`
+	Trips      []testTrip          `json:"Trips,omitempty" odata:"navigation"`
+}
+
+type testExpandedEmployee struct {
+	testExpandedPerson
+	Cost int
+}
+
+func TestNavigationProperties(t *testing.T) {
+	assert.Equal(t, []string{"BestFriend", "Trips"}, navigationProperties(testExpandedPerson{}))
+	assert.Equal(t, []string{"BestFriend", "Trips"}, navigationProperties(&testExpandedEmployee{}))
+	assert.Empty(t, navigationProperties(map[string]interface{}{}))
+	assert.Empty(t, navigationProperties(nil))
+}
+
+func TestNavigation_update_without_expanded_properties(t *testing.T) {
+	bodies := map[string]map[string]interface{}{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method == "GET" {
+			_, _ = writer.Write([]byte(`{"Id":5,"Name":"Russell","BestFriend":{"Id":6,"Name":"Scott"},"Trips":[{"TripId":1,"Name":"Trip"}]}`))
+			return
+		}
+		var body map[string]interface{}
+		data, _ := ioutil.ReadAll(request.Body)
+		_ = json.Unmarshal(data, &body)
+		bodies[request.Method] = body
+		writer.WriteHeader(204)
+	}))
+	defer testServer.Close()
+
+	people := testModelDefinition[testExpandedPerson]{client: New(testServer.URL)}.DataSet()
+	person, err := people.Single(context.Background(), Key(5), ODataFilter{Expand: []ODataExpand{{Property: "BestFriend"}, {Property: "Trips"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Scott", person.BestFriend.Name)
+
+	person.Name = "Russ"
+	_, err = people.Update(context.Background(), Key(5), person)
+	assert.NoError(t, err)
+	_, err = people.Replace(context.Background(), Key(5), person)
+	assert.NoError(t, err)
+	_, err = people.Insert(context.Background(), person)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"Id": float64(5), "Name": "Russ"}, bodies["PATCH"])
+	assert.Equal(t, map[string]interface{}{"Id": float64(5), "Name": "Russ"}, bodies["PUT"])
+	assert.Contains(t, bodies["POST"], "BestFriend")
+}
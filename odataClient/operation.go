@@ -0,0 +1,111 @@
+package odataClient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Uffe-Code/go-odata/date"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type serviceRoot struct {
+	client *oDataClient
+}
+
+func (root serviceRoot) getClient() *oDataClient {
+	return root.client
+}
+
+func (root serviceRoot) getPath() string {
+	return ""
+}
+
+// ServiceRoot returns the target for unbound functions and actions, which are called by the name of their import
+func ServiceRoot(client ODataClient) NavigationSource {
+	return serviceRoot{client: client.(*oDataClient)}
+}
+
+func operationUrl(target NavigationSource, name string) string {
+	path := target.getPath()
+	if path == "" {
+		return target.getClient().baseUrl + name
+	}
+	return target.getClient().baseUrl + path + "/" + name
+}
+
+// CallFunction calls the function with GET. The parameters are passed as parameter aliases, for example
+// GetNearestAirport(lat=@lat,lon=@lon)?@lat=33&@lon=-118. Bound functions must use the namespace qualified name.
+func CallFunction[ResultT any](ctx context.Context, target NavigationSource, name string, parameters map[string]interface{}, options ...RequestOption) (ResultT, error) {
+	var result ResultT
+	keys := sortedKeys(parameters)
+	aliases := make([]string, len(keys))
+	queryStrings := url.Values{}
+	for i, key := range keys {
+		aliases[i] = key + "=@" + key
+		value, err := formatParameterValue(parameters[key])
+		if err != nil {
+			return result, err
+		}
+		queryStrings.Set("@"+key, value)
+	}
+	requestUrl := operationUrl(target, name) + "(" + strings.Join(aliases, ",") + ")"
+	if len(keys) > 0 {
+		requestUrl += "?" + queryStrings.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+	if err != nil {
+		return result, err
+	}
+	applyRequestOptions(request, options)
+	_, body, err := executeRawHttpRequest(*target.getClient(), request)
+	if err != nil || len(body) == 0 {
+		return result, err
+	}
+	return decodeEntity[ResultT](body)
+}
+
+// CallAction calls the action with POST, sending the parameters as JSON body. Bound actions must use the
+// namespace qualified name. If the action has no return type, use struct{} as ResultT.
+func CallAction[ResultT any](ctx context.Context, target NavigationSource, name string, parameters map[string]interface{}, options ...RequestOption) (ResultT, error) {
+	if parameters == nil {
+		parameters = map[string]interface{}{}
+	}
+	var result ResultT
+	response, err := writeModel[json.RawMessage](ctx, *target.getClient(), "POST", operationUrl(target, name), parameters, nil, options)
+	if err != nil || len(response) == 0 {
+		return result, err
+	}
+	return decodeEntity[ResultT](response)
+}
+
+// formatParameterValue formats primitive values as OData literals, and complex values and collections as JSON
+func formatParameterValue(value interface{}) (string, error) {
+	switch value.(type) {
+	case nil, Literal, time.Time, date.Date, []byte:
+		return formatLiteral(value), nil
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Pointer:
+		if reflectValue.IsNil() {
+			return "null", nil
+		}
+		return formatParameterValue(reflectValue.Elem().Interface())
+	case reflect.Struct:
+		if isNullableType(reflectValue.Type()) {
+			if !reflectValue.FieldByName("IsValid").Bool() {
+				return "null", nil
+			}
+			return formatParameterValue(reflectValue.FieldByName("Data").Interface())
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return formatLiteral(value), nil
+	}
+	jsonData, err := json.Marshal(value)
+	return string(jsonData), err
+}
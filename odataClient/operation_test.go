@@ -0,0 +1,97 @@
+package odataClient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Uffe-Code/go-nullable/nullable"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFormatParameterValue(t *testing.T) {
+	value, _ := formatParameterValue("O'Brien")
+	assert.Equal(t, "'O''Brien'", value)
+	value, _ = formatParameterValue(33.5)
+	assert.Equal(t, "33.5", value)
+	value, _ = formatParameterValue(nullable.Null[int]())
+	assert.Equal(t, "null", value)
+	name := "Trip"
+	value, _ = formatParameterValue(&name)
+	assert.Equal(t, "'Trip'", value)
+	value, _ = formatParameterValue((*string)(nil))
+	assert.Equal(t, "null", value)
+	value, _ = formatParameterValue([]string{"a", "b"})
+	assert.Equal(t, `["a","b"]`, value)
+	value, _ = formatParameterValue(testTrip{TripId: 1, Name: "Trip"})
+	assert.Equal(t, `{"TripId":1,"Name":"Trip"}`, value)
+}
+
+func TestCallFunction(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/GetNearestAirport(lat=@lat,lon=@lon)":
+			if request.URL.Query().Get("@lat") != "33" || request.URL.Query().Get("@lon") != "-118.5" {
+				writer.WriteHeader(400)
+				return
+			}
+			_, _ = writer.Write([]byte(`{"@odata.context":"$metadata#Airports/$entity","Id":1,"Name":"LAX"}`))
+		case "/People('russellwhyte')/Trippin.GetFriendsTrips(userName=@userName)":
+			_, _ = writer.Write([]byte(`{"@odata.context":"$metadata#Trips","value":[{"TripId":1},{"TripId":2}]}`))
+		case "/People/Trippin.CountPeople()":
+			_, _ = writer.Write([]byte(`{"@odata.context":"$metadata#Edm.Int32","value":42}`))
+		default:
+			writer.WriteHeader(404)
+		}
+	}))
+	defer testServer.Close()
+
+	client := New(testServer.URL)
+	airport, err := CallFunction[testModel](context.Background(), ServiceRoot(client), "GetNearestAirport", map[string]interface{}{"lat": 33, "lon": -118.5})
+	assert.NoError(t, err)
+	assert.Equal(t, "LAX", airport.Name)
+
+	people := newTestModelDefinition(client).DataSet()
+	trips, err := CallFunction[[]testTrip](context.Background(), people.Entity(Key("russellwhyte")), "Trippin.GetFriendsTrips", map[string]interface{}{"userName": "scott"})
+	assert.NoError(t, err)
+	assert.Len(t, trips, 2)
+
+	count, err := CallFunction[int](context.Background(), people, "Trippin.CountPeople", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func TestCallAction(t *testing.T) {
+	var body map[string]interface{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != "POST" {
+			writer.WriteHeader(405)
+			return
+		}
+		body = nil
+		data, _ := ioutil.ReadAll(request.Body)
+		_ = json.Unmarshal(data, &body)
+		switch request.URL.Path {
+		case "/People('russellwhyte')/Trippin.UpdateLastName":
+			_, _ = writer.Write([]byte(`{"@odata.context":"$metadata#Edm.Boolean","value":true}`))
+		case "/ResetDataSource":
+			writer.WriteHeader(204)
+		default:
+			writer.WriteHeader(404)
+		}
+	}))
+	defer testServer.Close()
+
+	client := New(testServer.URL)
+	people := newTestModelDefinition(client).DataSet()
+	updated, err := CallAction[bool](context.Background(), people.Entity(Key("russellwhyte")), "Trippin.UpdateLastName", map[string]interface{}{"lastName": "Whyte"})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, map[string]interface{}{"lastName": "Whyte"}, body)
+
+	_, err = CallAction[struct{}](context.Background(), ServiceRoot(client), "ResetDataSource", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, body)
+}
@@ -0,0 +1,130 @@
+package odataClient
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ODataFilter represents the OData system query options of a request. The filter can be given
+// either as a raw Filter string or as a built Expression, if both are given they are combined with "and".
+// Use Count of the data set to count the models matching the filter.
+type ODataFilter struct {
+	Filter     string
+	Expression FilterExpression
+	Select     []string
+	Expand     []ODataExpand
+	OrderBy    []OrderBy
+	Top        int
+	Skip       int
+	Search     string
+	Custom     map[string]string
+}
+
+// ODataExpand represents a navigation property to expand, with its own nested query options
+type ODataExpand struct {
+	Property string
+	Options  ODataFilter
+}
+
+// OrderBy represents a single sort key of the $orderby query option
+type OrderBy struct {
+	Property   string
+	Descending bool
+}
+
+// Asc sorts by the property in ascending order
+func Asc(property string) OrderBy {
+	return OrderBy{Property: property}
+}
+
+// Desc sorts by the property in descending order
+func Desc(property string) OrderBy {
+	return OrderBy{Property: property, Descending: true}
+}
+
+func (orderBy OrderBy) String() string {
+	if orderBy.Descending {
+		return orderBy.Property + " desc"
+	}
+	return orderBy.Property + " asc"
+}
+
+func (expand ODataExpand) String() string {
+	options := expand.Options.queryOptions()
+	if len(options) == 0 {
+		return expand.Property
+	}
+	keys := sortedKeys(options)
+	nested := make([]string, len(keys))
+	for i, key := range keys {
+		nested[i] = key + "=" + options[key]
+	}
+	return expand.Property + "(" + strings.Join(nested, ";") + ")"
+}
+
+func (filter ODataFilter) filterString() string {
+	if filter.Expression == nil {
+		return filter.Filter
+	}
+	if filter.Filter == "" {
+		return filter.Expression.String()
+	}
+	return And(Group(Raw(filter.Filter)), filter.Expression).String()
+}
+
+// queryOptions returns the system query options, without custom query options
+func (filter ODataFilter) queryOptions() map[string]string {
+	options := map[string]string{}
+	if filterString := filter.filterString(); filterString != "" {
+		options["$filter"] = filterString
+	}
+	if len(filter.Select) > 0 {
+		options["$select"] = strings.Join(filter.Select, ",")
+	}
+	if len(filter.Expand) > 0 {
+		expands := make([]string, len(filter.Expand))
+		for i, expand := range filter.Expand {
+			expands[i] = expand.String()
+		}
+		options["$expand"] = strings.Join(expands, ",")
+	}
+	if len(filter.OrderBy) > 0 {
+		orderBys := make([]string, len(filter.OrderBy))
+		for i, orderBy := range filter.OrderBy {
+			orderBys[i] = orderBy.String()
+		}
+		options["$orderby"] = strings.Join(orderBys, ",")
+	}
+	if filter.Top > 0 {
+		options["$top"] = strconv.Itoa(filter.Top)
+	}
+	if filter.Skip > 0 {
+		options["$skip"] = strconv.Itoa(filter.Skip)
+	}
+	if filter.Search != "" {
+		options["$search"] = filter.Search
+	}
+	return options
+}
+
+func (filter ODataFilter) toQueryString() string {
+	queryStrings := url.Values{}
+	for key, value := range filter.queryOptions() {
+		queryStrings.Set(key, value)
+	}
+	for key, value := range filter.Custom {
+		queryStrings.Set(key, value)
+	}
+	return queryStrings.Encode()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package odataClient
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestODataFilter_toQueryString(t *testing.T) {
+	assert.Equal(t, "", ODataFilter{}.toQueryString())
+	assert.Equal(t, "%24filter=Name+eq+%27Foo%27", ODataFilter{Filter: "Name eq 'Foo'"}.toQueryString())
+	assert.Equal(t, "%24filter=Name+eq+%27Foo%27", ODataFilter{Expression: Eq("Name", "Foo")}.toQueryString())
+	assert.Equal(t, "(Age gt 5) and Name eq 'Foo'", ODataFilter{Filter: "Age gt 5", Expression: Eq("Name", "Foo")}.filterString())
+}
+
+func TestODataFilter_queryOptions(t *testing.T) {
+	filter := ODataFilter{
+		Expression: Eq("Name", "Foo"),
+		Select:     []string{"UserName", "FirstName"},
+		Expand: []ODataExpand{
+			{Property: "Friends"},
+			{Property: "Trips", Options: ODataFilter{
+				Select:  []string{"Name"},
+				OrderBy: []OrderBy{Desc("StartsAt")},
+				Top:     2,
+				Expand:  []ODataExpand{{Property: "PlanItems"}},
+			}},
+		},
+		OrderBy: []OrderBy{Asc("LastName"), Desc("Age")},
+		Top:     10,
+		Skip:    20,
+		Search:  "blue OR green",
+		Custom:  map[string]string{"sap-client": "100"},
+	}
+	values, err := url.ParseQuery(filter.toQueryString())
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"$filter":    {"Name eq 'Foo'"},
+		"$select":    {"UserName,FirstName"},
+		"$expand":    {"Friends,Trips($expand=PlanItems;$orderby=StartsAt desc;$select=Name;$top=2)"},
+		"$orderby":   {"LastName asc,Age desc"},
+		"$top":       {"10"},
+		"$skip":      {"20"},
+		"$search":    {"blue OR green"},
+		"sap-client": {"100"},
+	}, values)
+}
@@ -0,0 +1,30 @@
+package odataClient
+
+import "net/http"
+
+// RequestOption modifies a single request before it is sent, for example by adding headers
+type RequestOption func(request *http.Request)
+
+// WithHeader sets a custom HTTP header on the request
+func WithHeader(key string, value string) RequestOption {
+	return func(request *http.Request) {
+		request.Header.Set(key, value)
+	}
+}
+
+// ReturnMinimal asks the service to respond without the model (204 No Content). The model that
+// was sent is returned instead.
+func ReturnMinimal() RequestOption {
+	return WithHeader("Prefer", "return=minimal")
+}
+
+// ReturnRepresentation asks the service to respond with the model as it was stored, this is the default
+func ReturnRepresentation() RequestOption {
+	return WithHeader("Prefer", "return=representation")
+}
+
+func applyRequestOptions(request *http.Request, options []RequestOption) {
+	for _, option := range options {
+		option(request)
+	}
+}
@@ -0,0 +1,12 @@
+package odataClient
+
+// SingletonSet is a single entity exposed by the entity container, like Me in TripPin. A singleton has no key,
+// so it is used like an entity, and Navigate and NavigateSingle can be used for its navigation properties.
+type SingletonSet[ModelT any] interface {
+	ODataEntity[ModelT]
+}
+
+// NewSingletonSet returns the singleton with the name, for example Me
+func NewSingletonSet[ModelT any](client ODataClient, name string) SingletonSet[ModelT] {
+	return newEntity[ModelT](client.(*oDataClient), name)
+}
@@ -0,0 +1,48 @@
+package odataClient
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSingletonSet(t *testing.T) {
+	var patchBody string
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.Method + " " + request.URL.Path {
+		case "GET /Me":
+			_, _ = writer.Write([]byte(`{"@odata.context":"$metadata#Me","Id":1,"Name":"Russell"}`))
+		case "PATCH /Me":
+			data, _ := ioutil.ReadAll(request.Body)
+			patchBody = string(data)
+			writer.WriteHeader(204)
+		case "GET /Me/Trips":
+			_, _ = writer.Write([]byte(`{"value":[{"TripId":1,"Name":"Trip 1"}]}`))
+		default:
+			writer.WriteHeader(404)
+		}
+	}))
+	defer testServer.Close()
+
+	me := NewSingletonSet[testModel](New(testServer.URL), "Me")
+	model, err := me.Get(context.Background(), ODataFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Russell", model.Name)
+
+	model.Name = "Scott"
+	model, err = me.Update(context.Background(), model)
+	assert.NoError(t, err)
+	assert.Equal(t, "Scott", model.Name)
+	assert.Contains(t, patchBody, `"Name":"Scott"`)
+
+	trips, errs := Navigate[testTrip](me, "Trips").List(context.Background(), ODataFilter{})
+	var names []string
+	for trip := range trips {
+		names = append(names, trip.Name)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []string{"Trip 1"}, names)
+}
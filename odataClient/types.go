@@ -0,0 +1,187 @@
+package odataClient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// typeRegistry maps the namespace qualified names of entity and complex types to their Go types, and interface
+// types to the Go type used when a value has no or an unknown @odata.type
+type typeRegistry struct {
+	mutex     sync.RWMutex
+	types     map[string]reflect.Type
+	fallbacks map[reflect.Type]reflect.Type
+}
+
+var registeredTypes = &typeRegistry{
+	types:     map[string]reflect.Type{},
+	fallbacks: map[reflect.Type]reflect.Type{},
+}
+
+// RegisterType registers ModelT as the Go type of the entity or complex type with the namespace qualified name,
+// so values with "@odata.type": "#Trippin.Employee" are decoded into it. Generated models register themselves.
+func RegisterType[ModelT any](qualifiedName string) {
+	registeredTypes.mutex.Lock()
+	defer registeredTypes.mutex.Unlock()
+	registeredTypes.types[strings.TrimPrefix(qualifiedName, "#")] = reflect.TypeOf((*ModelT)(nil)).Elem()
+}
+
+// RegisterFallbackType registers ModelT as the Go type for values of the interface InterfaceT which have no or
+// an unknown @odata.type, which is usually the base type of the types implementing the interface
+func RegisterFallbackType[InterfaceT any, ModelT any]() {
+	registeredTypes.mutex.Lock()
+	defer registeredTypes.mutex.Unlock()
+	interfaceType := reflect.TypeOf((*InterfaceT)(nil)).Elem()
+	registeredTypes.fallbacks[interfaceType] = reflect.TypeOf((*ModelT)(nil)).Elem()
+}
+
+// lookup returns the Go type for the @odata.type of a value decoded into the interface type
+func (registry *typeRegistry) lookup(odataType string, interfaceType reflect.Type) reflect.Type {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	if index := strings.LastIndex(odataType, "#"); index >= 0 {
+		odataType = odataType[index+1:]
+	}
+	if modelType, ok := registry.types[odataType]; ok && modelType.Implements(interfaceType) {
+		return modelType
+	}
+	if fallbackType, ok := registry.fallbacks[interfaceType]; ok && fallbackType.Implements(interfaceType) {
+		return fallbackType
+	}
+	return nil
+}
+
+// decodeValue decodes the JSON into ModelT. When ModelT is an interface, the value is decoded into the
+// registered type of its @odata.type, or into the fallback type of the interface.
+func decodeValue[ModelT any](data []byte) (ModelT, error) {
+	var model ModelT
+	err := decodeInto(data, reflect.ValueOf(&model).Elem())
+	return model, err
+}
+
+// decodeInto decodes the JSON into the addressable value like decodeValue. Elements of slices and arrays
+// of interfaces are decoded by their @odata.type as well.
+func decodeInto(data []byte, value reflect.Value) error {
+	valueType := value.Type()
+	switch valueType.Kind() {
+	case reflect.Interface:
+		return decodeInterface(data, value)
+	case reflect.Slice, reflect.Array:
+		if elementsAreInterfaces(valueType) {
+			return decodeElements(data, value)
+		}
+	}
+	return json.Unmarshal(data, value.Addr().Interface())
+}
+
+// decodeInterface decodes a JSON object into the registered type of its @odata.type, or into the fallback type
+// of the interface. Other JSON values, like null, are decoded by json.Unmarshal.
+func decodeInterface(data []byte, value reflect.Value) error {
+	interfaceType := value.Type()
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		return json.Unmarshal(data, value.Addr().Interface())
+	}
+
+	var controlInformation struct {
+		Type string `json:"@odata.type"`
+	}
+	if err := json.Unmarshal(data, &controlInformation); err != nil {
+		return err
+	}
+	modelType := registeredTypes.lookup(controlInformation.Type, interfaceType)
+	if modelType == nil {
+		if interfaceType.NumMethod() > 0 {
+			return fmt.Errorf("no type registered for %s with @odata.type %q", interfaceType, controlInformation.Type)
+		}
+		return json.Unmarshal(data, value.Addr().Interface())
+	}
+
+	model := reflect.New(modelType)
+	if err := json.Unmarshal(data, model.Interface()); err != nil {
+		return err
+	}
+	value.Set(model.Elem())
+	return nil
+}
+
+// decodeElements decodes a JSON array into a slice or an array, each element with decodeInto
+func decodeElements(data []byte, value reflect.Value) error {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	if elements == nil {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+	decoded := reflect.New(value.Type()).Elem()
+	if value.Kind() == reflect.Slice {
+		decoded = reflect.MakeSlice(value.Type(), len(elements), len(elements))
+	} else if len(elements) > decoded.Len() {
+		elements = elements[:decoded.Len()]
+	}
+	for i, element := range elements {
+		if err := decodeInto(element, decoded.Index(i)); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	value.Set(decoded)
+	return nil
+}
+
+// elementsAreInterfaces returns true for slices and arrays of interfaces, and of slices or arrays of them
+func elementsAreInterfaces(valueType reflect.Type) bool {
+	for valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Array {
+		valueType = valueType.Elem()
+	}
+	return valueType.Kind() == reflect.Interface
+}
+
+// UnmarshalModel decodes the JSON object into the struct the model points to, like json.Unmarshal, but decodes
+// the values of interface fields, like AnyLocation, into the registered type of their @odata.type. Properties are
+// matched by the exact name of their json tag. Generated models with polymorphic properties call it from UnmarshalJSON.
+func UnmarshalModel(data []byte, model interface{}) error {
+	value := reflect.ValueOf(model)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal into %T, which is not a pointer to a struct", model)
+	}
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return err
+	}
+	return unmarshalFields(properties, value.Elem())
+}
+
+// unmarshalFields decodes the properties into the fields of the struct, and the fields of its embedded structs
+func unmarshalFields(properties map[string]json.RawMessage, model reflect.Value) error {
+	modelType := model.Type()
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			if err := unmarshalFields(properties, model.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if data, ok := properties[name]; ok {
+			if err := decodeInto(data, model.Field(i)); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package odataClient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testAnyPerson interface {
+	AsTestPerson() testPerson
+}
+
+type testPerson struct {
+	UserName string
+}
+
+func (m testPerson) AsTestPerson() testPerson {
+	return m
+}
+
+type testEmployee struct {
+	testPerson
+	Cost int64
+}
+
+func init() {
+	RegisterType[testPerson]("Test.Person")
+	RegisterType[testEmployee]("Test.Employee")
+	RegisterFallbackType[testAnyPerson, testPerson]()
+}
+
+func TestDecodeValue(t *testing.T) {
+	person, err := decodeValue[testAnyPerson]([]byte(`{"@odata.type":"#Test.Employee","UserName":"russellwhyte","Cost":100}`))
+	assert.NoError(t, err)
+	assert.Equal(t, testEmployee{testPerson: testPerson{UserName: "russellwhyte"}, Cost: 100}, person)
+
+	person, err = decodeValue[testAnyPerson]([]byte(`{"@odata.type":"http://test.api/$metadata#Test.Person","UserName":"scott"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, testPerson{UserName: "scott"}, person)
+
+	person, err = decodeValue[testAnyPerson]([]byte(`{"@odata.type":"#Test.Unknown","UserName":"ronald"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, testPerson{UserName: "ronald"}, person)
+
+	person, err = decodeValue[testAnyPerson]([]byte(`{"UserName":"javier"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "javier", person.AsTestPerson().UserName)
+
+	value, err := decodeValue[interface{}]([]byte(`{"@odata.type":"#Test.Employee","UserName":"russellwhyte"}`))
+	assert.NoError(t, err)
+	assert.IsType(t, testEmployee{}, value)
+
+	_, err = decodeValue[Literal]([]byte(`{"@odata.type":"#Test.Employee"}`))
+	assert.Error(t, err)
+}
+
+func TestDataSet_List_derivedTypes(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`{"value":[
+			{"@odata.type":"#Test.Person","UserName":"scott"},
+			{"@odata.type":"#Test.Employee","UserName":"russellwhyte","Cost":100}
+		]}`))
+	}))
+	defer testServer.Close()
+
+	people := NewDataSet[testAnyPerson](New(testServer.URL), pathDefinition[testAnyPerson]{name: "People", url: "People"})
+	models, errs := people.List(context.Background(), ODataFilter{})
+	var decoded []testAnyPerson
+	for model := range models {
+		decoded = append(decoded, model)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []testAnyPerson{
+		testPerson{UserName: "scott"},
+		testEmployee{testPerson: testPerson{UserName: "russellwhyte"}, Cost: 100},
+	}, decoded)
+}
+
+type testTeam struct {
+	EntityMetadata
+	Name    string          `json:"Name"`
+	Leader  testAnyPerson   `json:"Leader"`
+	Members []testAnyPerson `json:"Members"`
+}
+
+func (m *testTeam) UnmarshalJSON(data []byte) error {
+	return UnmarshalModel(data, m)
+}
+
+type testDivision struct {
+	testTeam
+	Budget int64 `json:"Budget"`
+}
+
+func (m *testDivision) UnmarshalJSON(data []byte) error {
+	return UnmarshalModel(data, m)
+}
+
+func TestUnmarshalModel(t *testing.T) {
+	var division testDivision
+	err := json.Unmarshal([]byte(`{
+		"@odata.etag": "W/\"1\"",
+		"Name": "Sales",
+		"Budget": 1000,
+		"Leader": {"@odata.type": "#Test.Employee", "UserName": "russellwhyte", "Cost": 100},
+		"Members": [{"UserName": "scott"}, {"@odata.type": "#Test.Employee", "UserName": "ronald", "Cost": 50}]
+	}`), &division)
+	assert.NoError(t, err)
+	assert.Equal(t, testDivision{
+		testTeam: testTeam{
+			EntityMetadata: EntityMetadata{ETag: `W/"1"`},
+			Name:           "Sales",
+			Leader:         testEmployee{testPerson: testPerson{UserName: "russellwhyte"}, Cost: 100},
+			Members: []testAnyPerson{
+				testPerson{UserName: "scott"},
+				testEmployee{testPerson: testPerson{UserName: "ronald"}, Cost: 50},
+			},
+		},
+		Budget: 1000,
+	}, division)
+
+	var team testTeam
+	assert.NoError(t, json.Unmarshal([]byte(`{"Name": "Support", "Leader": null, "Members": null}`), &team))
+	assert.Equal(t, testTeam{Name: "Support"}, team)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"Leader": {"UserName": 1}}`), &team))
+	assert.Error(t, UnmarshalModel([]byte(`{}`), team))
+}
+
+func TestCallFunction_derivedTypes(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`{"@odata.context":"$metadata#People","value":[
+			{"@odata.type":"#Test.Person","UserName":"scott"},
+			{"@odata.type":"#Test.Employee","UserName":"russellwhyte","Cost":100}
+		]}`))
+	}))
+	defer testServer.Close()
+
+	people, err := CallFunction[[]testAnyPerson](context.Background(), ServiceRoot(New(testServer.URL)), "GetInvolvedPeople", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []testAnyPerson{
+		testPerson{UserName: "scott"},
+		testEmployee{testPerson: testPerson{UserName: "russellwhyte"}, Cost: 100},
+	}, people)
+
+	pairs, err := decodeValue[[][2]testAnyPerson]([]byte(`[[{"UserName":"scott"},null,{"UserName":"ronald"}]]`))
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]testAnyPerson{{testPerson{UserName: "scott"}, nil}}, pairs)
+
+	_, err = decodeValue[[]testAnyPerson]([]byte(`[{"UserName":1}]`))
+	assert.Error(t, err)
+}
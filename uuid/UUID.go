@@ -0,0 +1,75 @@
+package uuid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UUID represents an Edm.Guid value
+type UUID [16]byte
+
+// Nil is the UUID with all bits set to zero
+var Nil UUID
+
+// New returns a random version 4 UUID
+func New() UUID {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(err)
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// Parse parses a UUID like 01234567-89ab-cdef-0123-456789abcdef, optionally in braces
+func Parse(value string) (UUID, error) {
+	var u UUID
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(value, "{"), "}")
+	if len(trimmed) != 36 || trimmed[8] != '-' || trimmed[13] != '-' || trimmed[18] != '-' || trimmed[23] != '-' {
+		return u, fmt.Errorf("invalid UUID %q", value)
+	}
+	if _, err := hex.Decode(u[:], []byte(strings.ReplaceAll(trimmed, "-", ""))); err != nil {
+		return u, fmt.Errorf("invalid UUID %q", value)
+	}
+	return u, nil
+}
+
+// MustParse parses the UUID, and panics if it is invalid
+func MustParse(value string) UUID {
+	u, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func (u UUID) String() string {
+	encoded := hex.EncodeToString(u[:])
+	return encoded[0:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:]
+}
+
+// ODataLiteral formats the UUID as an OData URL literal
+func (u UUID) ODataLiteral() string {
+	return u.String()
+}
+
+func (u UUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+func (u *UUID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	var err error
+	*u, err = Parse(value)
+	return err
+}
@@ -0,0 +1,46 @@
+package uuid
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Parse(t *testing.T) {
+	u, err := Parse("01234567-89AB-cdef-0123-456789abcdef")
+	assert.NoError(t, err)
+	assert.Equal(t, "01234567-89ab-cdef-0123-456789abcdef", u.String())
+	assert.Equal(t, "01234567-89ab-cdef-0123-456789abcdef", u.ODataLiteral())
+
+	braced, err := Parse("{01234567-89ab-cdef-0123-456789abcdef}")
+	assert.NoError(t, err)
+	assert.Equal(t, u, braced)
+
+	for _, invalid := range []string{"", "0123456789abcdef0123456789abcdef", "01234567-89ab-cdef-0123-456789abcdeg", "01234567-89ab-cdef-0123-456789abcdef0"} {
+		_, err = Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func Test_New(t *testing.T) {
+	u := New()
+	assert.NotEqual(t, Nil, u)
+	assert.NotEqual(t, u, New())
+	assert.Equal(t, byte(4), u[6]>>4)
+	assert.Equal(t, byte(2), u[8]>>6)
+}
+
+func Test_Json(t *testing.T) {
+	type res struct {
+		ShareId UUID `json:"shareId"`
+	}
+	value := res{MustParse("9d9b2fa0-efbf-490e-a5e3-bac8f7d47354")}
+	jsonData, err := json.Marshal(value)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"shareId":"9d9b2fa0-efbf-490e-a5e3-bac8f7d47354"}`, string(jsonData))
+
+	var data res
+	assert.NoError(t, json.Unmarshal(jsonData, &data))
+	assert.Equal(t, value, data)
+	assert.Error(t, json.Unmarshal([]byte(`{"shareId":"nope"}`), &data))
+}